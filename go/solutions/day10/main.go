@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"slices"
 	"strconv"
 	"strings"
 
@@ -31,41 +30,21 @@ func part1(filename string) {
 	machines := makeMachines(lines)
 
 	for _, m := range machines {
-		start := initIndicators(len(m.indicators))
-		pressed := make(map[string]int)
-		pressed[serialize(start)] = 0
-		queue := make([][]int, 0)
-		queue = append(queue, start)
-
-		for len(queue) > 0 {
-			curr := queue[0]
-			queue = queue[1:]
-
-			if slices.Equal(curr, m.indicators) {
-				result += pressed[serialize(curr)]
-				break
-			}
+		start := serialize(initIndicators(len(m.indicators)))
+		target := serialize(m.indicators)
 
+		res := util.SearchBFS(start, func(s string) bool {
+			return s == target
+		}, func(s string) []util.Edge[string] {
 			// generate next states
-			steps := pressed[serialize(curr)]
+			next := make([]util.Edge[string], 0, len(m.ops))
 			for _, op := range m.ops {
-				newIndicators := make([]int, len(curr))
-				copy(newIndicators, curr)
-				for _, idx := range op {
-					newIndicators[idx] *= -1
-				}
-
-				ser := serialize(newIndicators)
-
-				if _, ok := pressed[ser]; !ok {
-					pressed[ser] = steps + 1
-					queue = append(queue, newIndicators)
-				} else if pressed[ser] > steps+1 {
-					pressed[ser] = steps + 1
-					queue = append(queue, newIndicators)
-				}
+				next = append(next, util.Edge[string]{To: toggle(s, op), Cost: 1})
 			}
-		}
+			return next
+		})
+
+		result += res.Cost
 	}
 
 	fmt.Println(result)
@@ -96,6 +75,19 @@ func initIndicators(num int) []int {
 	return indicators
 }
 
+// toggle flips the serialized indicators at each index in op
+func toggle(state string, op []int) string {
+	b := []byte(state)
+	for _, idx := range op {
+		if b[idx] == '0' {
+			b[idx] = '1'
+		} else {
+			b[idx] = '0'
+		}
+	}
+	return string(b)
+}
+
 func serialize(indicators []int) string {
 	var sb strings.Builder
 	for _, ind := range indicators {
@@ -26,7 +26,10 @@ func part1(filename string) {
 		graph[parts[0]] = parts[1:]
 	}
 
-	result = util.CountAllPaths(graph, "you", "out", make(map[string]int64))
+	result, err := util.CountAllPaths(graph, "you", "out", make(map[string]int64))
+	if err != nil {
+		panic(err)
+	}
 
 	fmt.Println(result)
 }
@@ -41,23 +44,10 @@ func part2(filename string) {
 		graph[parts[0]] = parts[1:]
 	}
 
-	// possibilities
-	// svr -> fft -> dac -> out
-	// svr -> dac -> fft -> out
-	poss1 := []string{"svr", "fft", "dac", "out"}
-	poss2 := []string{"svr", "dac", "fft", "out"}
-
-	results := []int64{}
-	for _, poss := range [][]string{poss1, poss2} {
-		steps := [3]int64{}
-		for i := 0; i < len(poss)-1; i++ {
-			curr := poss[i]
-			next := poss[i+1]
-			steps[i] = util.CountAllPaths(graph, curr, next, make(map[string]int64))
-		}
-
-		results = append(results, steps[0]*steps[1]*steps[2])
+	result, err := util.CountPathsThrough(graph, "svr", "out", []string{"fft", "dac"})
+	if err != nil {
+		panic(err)
 	}
 
-	fmt.Println(results[0] + results[1])
+	fmt.Println(result)
 }
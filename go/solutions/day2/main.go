@@ -18,8 +18,8 @@ func part1(filename string) {
 	ranges := parseInput(filename)
 
 	for _, r := range ranges {
-		curr := r.start
-		for curr <= r.end {
+		curr := r.Start
+		for curr < r.End {
 			if detectRepeat(curr) {
 				result += curr
 			}
@@ -35,8 +35,8 @@ func part2(filename string) {
 	ranges := parseInput(filename)
 
 	for _, r := range ranges {
-		curr := r.start
-		for curr <= r.end {
+		curr := r.Start
+		for curr < r.End {
 			if detectRepeat(curr) {
 				result += curr
 			}
@@ -47,13 +47,8 @@ func part2(filename string) {
 	fmt.Println(result)
 }
 
-type Range struct {
-	start int
-	end   int
-}
-
-func parseInput(filename string) []Range {
-	result := []Range{}
+func parseInput(filename string) []util.Interval {
+	result := []util.Interval{}
 	lines := util.GetLines(filename)
 	for _, line := range lines {
 		ranges := strings.Split(line, ",")
@@ -62,7 +57,7 @@ func parseInput(filename string) []Range {
 			start := util.MustConvAtoi(rangeParts[0])
 			end := util.MustConvAtoi(rangeParts[1])
 
-			result = append(result, Range{start, end})
+			result = append(result, util.Inclusive(start, end))
 		}
 	}
 	return result
@@ -2,26 +2,12 @@ package main
 
 import (
 	"fmt"
-	"math"
-	"slices"
 	"strings"
 
 	"github.com/smort/aoc2025/util"
+	"github.com/smort/aoc2025/util/geom"
 )
 
-type Point3D struct {
-	X, Y, Z float64
-}
-
-type Pair struct {
-	I, J     int // indexes into point slice
-	Distance float64
-}
-
-type Graph struct {
-	Adj [][]int
-}
-
 func main() {
 	part1("example.txt", 10)
 	part1("input.txt", 1000)
@@ -31,86 +17,38 @@ func main() {
 }
 
 func part1(filename string, numConns int) {
-	lines := util.GetLines(filename)
-
-	// make array of points
-	points := make([]Point3D, 0, len(lines))
-	for _, line := range lines {
-		parts := strings.Split(line, ",")
-		x, y, z := util.MustConvAtoi(parts[0]), util.MustConvAtoi(parts[1]), util.MustConvAtoi(parts[2])
-		points = append(points, Point3D{float64(x), float64(y), float64(z)})
-	}
-
-	// calculate the distance between each pair of points (oof)
-	minHeap := allPairsMinHeap(points)
-
-	// make graph
-	g := buildGraph(len(points), minHeap, numConns)
-
-	// traverse to get sizes - bfs
-	n := len(g.Adj)
-	visited := make([]bool, n)
-	sizes := make([]int, 0, n)
-	for start := range n {
-		if visited[start] {
-			continue
-		}
-
-		queue := []int{start}
-		visited[start] = true
-		size := 0
-
-		for len(queue) > 0 {
-			curr := queue[0]
-			queue = queue[1:]
-			size++
-
-			for _, node := range g.Adj[curr] {
-				if !visited[node] {
-					visited[node] = true
-					queue = append(queue, node)
-				}
-			}
+	points := parsePoints(filename)
+
+	// connect the closest pairs, pulled off the kd tree in order
+	ds := util.NewDisjointSet(indexes(len(points))...)
+	conns := 0
+	for p := range geom.NewKDTree[util.Vec3, int](points).ClosestPairs() {
+		if conns == numConns {
+			break
 		}
-
-		sizes = append(sizes, size)
+		ds.Union(p.I, p.J)
+		conns++
 	}
 
-	slices.SortFunc(sizes, func(a, b int) int {
-		return b - a
-	})
+	sizes := util.NewTopK(3, func(a, b int) bool { return a > b })
+	for _, comp := range ds.Components() {
+		sizes.Add(len(comp))
+	}
 
 	var out [3]int
-	for i := 0; i < 3 && i < len(sizes); i++ {
-		out[i] = sizes[i]
-	}
+	copy(out[:], sizes.Items())
 
 	fmt.Println(out[0] * out[1] * out[2])
 }
 
 func part2(filename string, numConns int) {
-	lines := util.GetLines(filename)
-
-	// make array of points
-	points := make([]Point3D, 0, len(lines))
-	for _, line := range lines {
-		parts := strings.Split(line, ",")
-		x, y, z := util.MustConvAtoi(parts[0]), util.MustConvAtoi(parts[1]), util.MustConvAtoi(parts[2])
-		points = append(points, Point3D{float64(x), float64(y), float64(z)})
-	}
-
-	// calculate the distance between each pair of points (oof)
-	minHeap := allPairsMinHeap(points)
-
-	g := Graph{Adj: make([][]int, len(points))}
-	for minHeap.Len() > 0 {
-		p := minHeap.Pop()
-		g.Adj[p.I] = append(g.Adj[p.I], p.J)
-		g.Adj[p.J] = append(g.Adj[p.J], p.I)
+	points := parsePoints(filename)
 
-		// check if everything connected together
-		if isConnected(g) {
-			fmt.Printf("%f\n", points[p.I].X*points[p.J].X)
+	// kruskal's, stopping once everything is connected together
+	ds := util.NewDisjointSet(indexes(len(points))...)
+	for p := range geom.NewKDTree[util.Vec3, int](points).ClosestPairs() {
+		if ds.Union(p.I, p.J) && ds.NumComponents() == 1 {
+			fmt.Printf("%f\n", float64(points[p.I].X*points[p.J].X))
 			return
 		}
 	}
@@ -118,58 +56,22 @@ func part2(filename string, numConns int) {
 	fmt.Println("FAIL")
 }
 
-func distance(a, b Point3D) float64 {
-	dx := a.X - b.X
-	dy := a.Y - b.Y
-	dz := a.Z - b.Z
-	return math.Sqrt(dx*dx + dy*dy + dz*dz)
-}
-
-func allPairsMinHeap(points []Point3D) *util.MinHeap[Pair] {
-	heap := util.NewMinHeap[Pair]()
-
-	n := len(points)
-	for i := range n {
-		for j := i + 1; j < n; j++ {
-			d := distance(points[i], points[j])
-			heap.Push(Pair{I: i, J: j, Distance: d}, int(d))
-		}
-	}
-
-	return heap
-}
-
-func buildGraph(n int, heap *util.MinHeap[Pair], k int) Graph {
-	g := Graph{Adj: make([][]int, n)}
+func parsePoints(filename string) []util.Vec3 {
+	lines := util.GetLines(filename)
 
-	// keep connecting until we reach k nodes
-	for i := 0; i < k && heap.Len() > 0; i++ {
-		p := heap.Pop()
-		g.Adj[p.I] = append(g.Adj[p.I], p.J)
-		g.Adj[p.J] = append(g.Adj[p.J], p.I)
+	points := make([]util.Vec3, 0, len(lines))
+	for _, line := range lines {
+		parts := strings.Split(line, ",")
+		x, y, z := util.MustConvAtoi(parts[0]), util.MustConvAtoi(parts[1]), util.MustConvAtoi(parts[2])
+		points = append(points, util.Vec3{X: x, Y: y, Z: z})
 	}
-
-	return g
+	return points
 }
 
-func isConnected(g Graph) bool {
-	n := len(g.Adj)
-	visited := make([]bool, n)
-	queue := []int{0} // start at node 0
-	visited[0] = true
-	count := 1
-
-	for len(queue) > 0 {
-		curr := queue[0]
-		queue = queue[1:]
-		for _, node := range g.Adj[curr] {
-			if !visited[node] {
-				visited[node] = true
-				queue = append(queue, node)
-				count++
-			}
-		}
+func indexes(n int) []int {
+	out := make([]int, n)
+	for i := range n {
+		out[i] = i
 	}
-
-	return count == n
+	return out
 }
@@ -2,11 +2,10 @@ package main
 
 import (
 	"fmt"
-	"math"
-	"slices"
 	"strings"
 
 	"github.com/smort/aoc2025/util"
+	"github.com/smort/aoc2025/util/geom"
 )
 
 func main() {
@@ -17,31 +16,35 @@ func main() {
 	part2("input.txt")
 }
 
-type Point struct {
-	X, Y int
-}
-
-func part1(filename string) {
-	// result := 0
+func parsePoints(filename string) []util.Vec2 {
 	lines := util.GetLines(filename)
 
-	points := make([]Point, 0, len(lines))
+	points := make([]util.Vec2, 0, len(lines))
 	for _, line := range lines {
 		parts := strings.Split(line, ",")
-		points = append(points, Point{X: util.MustConvAtoi(parts[0]), Y: util.MustConvAtoi(parts[1])})
+		points = append(points, util.Vec2{X: util.MustConvAtoi(parts[0]), Y: util.MustConvAtoi(parts[1])})
 	}
+	return points
+}
+
+// area of the rectangle with corners a and b, counting tiles inclusively
+func rectArea(a, b util.Vec2) int {
+	d := a.Sub(b).Abs()
+	return (d.X + 1) * (d.Y + 1)
+}
+
+func part1(filename string) {
+	points := parsePoints(filename)
 
 	maxSize := 0
-	winningPoints := [2]Point{}
+	winningPoints := [2]util.Vec2{}
 	for _, point := range points {
 		for _, other := range points {
 			if point == other {
 				continue
 			}
 
-			x := math.Abs(float64(point.X-other.X)) + 1
-			y := math.Abs(float64(point.Y-other.Y)) + 1
-			area := int(x * y)
+			area := rectArea(point, other)
 			if area > maxSize {
 				maxSize = area
 				winningPoints[0] = point
@@ -55,85 +58,22 @@ func part1(filename string) {
 }
 
 func part2(filename string) {
-	lines := util.GetLines(filename)
+	points := parsePoints(filename)
+	polygon := geom.NewPolygon(points)
 
-	points := make([]Point, 0, len(lines))
-	for _, line := range lines {
-		parts := strings.Split(line, ",")
-		points = append(points, Point{X: util.MustConvAtoi(parts[0]), Y: util.MustConvAtoi(parts[1])})
-	}
-
-	edgePoints := collectAllEdgePoints(points)
 	maxArea := 0
-	for _, point := range points {
-		for _, other := range points {
-			if point == other {
-				continue
-			}
-
-			x := math.Abs(float64(point.X-other.X)) + 1
-			y := math.Abs(float64(point.Y-other.Y)) + 1
-			area := int(x * y)
+	for i, point := range points {
+		for _, other := range points[i+1:] {
+			area := rectArea(point, other)
 			if area <= maxArea {
 				continue // skip ones we've already beaten
 			}
 
-			// check if any points are inside. if so we've intersected the shape
-			isInvalid := false
-			for _, e := range edgePoints {
-				if isIn(e, point, other) {
-					isInvalid = true
-					break
-				}
-			}
-			if isInvalid {
-				continue
+			if polygon.ContainsRect(point, other) {
+				maxArea = area
 			}
-
-			maxArea = area
 		}
 	}
 
 	fmt.Println(maxArea)
 }
-
-func isIn(p, corner1, corner2 Point) bool {
-	minX := min(corner1.X, corner2.X)
-	maxX := max(corner1.X, corner2.X)
-	minY := min(corner1.Y, corner2.Y)
-	maxY := max(corner1.Y, corner2.Y)
-
-	return p.X > minX && p.X < maxX && p.Y > minY && p.Y < maxY
-}
-
-func collectAllEdgePoints(points []Point) []Point {
-	edgePoints := make([]Point, 0, len(points))
-	for i := range len(points) - 1 {
-		newEdgePoints := getEdgePoints(points[i], points[i+1])
-		edgePoints = slices.Concat(edgePoints, newEdgePoints)
-	}
-
-	newEdgePoints := getEdgePoints(points[len(points)-1], points[0]) // connect first and last
-	edgePoints = slices.Concat(edgePoints, newEdgePoints)
-
-	return edgePoints
-}
-
-func getEdgePoints(point1 Point, point2 Point) []Point {
-	points := make([]Point, 0)
-	if point1.X == point2.X { // vertical line
-		minY := min(point1.Y, point2.Y)
-		maxY := max(point1.Y, point2.Y)
-		for y := minY; y < maxY; y++ {
-			points = append(points, Point{X: point1.X, Y: y})
-		}
-	} else if point1.Y == point2.Y { // horizontal line
-		minX := min(point1.X, point2.X)
-		maxX := max(point1.X, point2.X)
-		for x := minX; x < maxX; x++ {
-			points = append(points, Point{X: x, Y: point1.Y})
-		}
-	}
-
-	return points
-}
@@ -0,0 +1,233 @@
+package util
+
+import (
+	"iter"
+	"math/big"
+	"slices"
+)
+
+// ShortestPathDAG records every optimal predecessor of each settled state, so
+// all optimal paths can be counted or walked without copying them during search
+type ShortestPathDAG[S comparable] struct {
+	Found    bool
+	Start    S
+	Goals    []S // every goal state reached at the optimal cost
+	Cost     int
+	Visited  int
+	Distance map[S]int
+	Preds    map[S][]S
+	// Infinite is set when a zero cost cycle lies on an optimal path to a goal, so
+	// there are infinitely many optimal paths
+	Infinite bool
+
+	order []S // settled states, each after all of its preds
+}
+
+// SearchDijkstraAll is SearchDijkstra but keeps every optimal predecessor.
+// negative edge costs are skipped. zero cost edges are fine, but a zero cost
+// cycle on the way to a goal means infinitely many optimal paths. that sets
+// Infinite, and Paths then only yields the paths that don't repeat a state
+func SearchDijkstraAll[S comparable](start S, goal GoalFunc[S], next SuccessorFunc[S]) *ShortestPathDAG[S] {
+	dag := &ShortestPathDAG[S]{
+		Start:    start,
+		Distance: map[S]int{start: 0},
+		Preds:    make(map[S][]S),
+	}
+
+	pq := NewIndexedMinHeap[S, int]()
+	pq.Upsert(start, 0)
+	closed := make(map[S]bool)
+
+	for pq.Len() > 0 {
+		current, cost := pq.PopMin()
+
+		// everything left is worse than the goals we already have
+		if dag.Found && cost > dag.Cost {
+			break
+		}
+
+		closed[current] = true
+		dag.Visited++
+		dag.order = append(dag.order, current)
+
+		if goal(current) {
+			dag.Found = true
+			dag.Cost = cost
+			dag.Goals = append(dag.Goals, current)
+			continue
+		}
+
+		for _, e := range next(current) {
+			if e.Cost < 0 || e.To == start {
+				continue
+			}
+
+			newCost := cost + e.Cost
+			oldCost, exists := dag.Distance[e.To]
+			switch {
+			case closed[e.To]:
+				// only a zero cost edge can tie with a state that's already settled
+				if newCost == oldCost {
+					dag.Preds[e.To] = append(dag.Preds[e.To], current)
+				}
+			case !exists || newCost < oldCost:
+				dag.Distance[e.To] = newCost
+				dag.Preds[e.To] = []S{current}
+				pq.Upsert(e.To, newCost)
+			case newCost == oldCost:
+				dag.Preds[e.To] = append(dag.Preds[e.To], current)
+			}
+		}
+	}
+
+	dag.sortSettled()
+	return dag
+}
+
+// sortSettled puts the settled states in an order where every state comes after
+// all of its preds. settle order isn't enough once zero cost edges add preds to
+// states that were already closed. states on or after a zero cost cycle never
+// come free, so they're left out, and a goal among them sets Infinite
+func (d *ShortestPathDAG[S]) sortSettled() {
+	indegree := make(map[S]int, len(d.order))
+	children := make(map[S][]S)
+	for _, s := range d.order {
+		indegree[s] = len(d.Preds[s])
+		for _, p := range d.Preds[s] {
+			children[p] = append(children[p], s)
+		}
+	}
+
+	order := make([]S, 0, len(d.order))
+	for _, s := range d.order {
+		if indegree[s] == 0 {
+			order = append(order, s)
+		}
+	}
+	for i := 0; i < len(order); i++ {
+		for _, c := range children[order[i]] {
+			indegree[c]--
+			if indegree[c] == 0 {
+				order = append(order, c)
+			}
+		}
+	}
+	d.order = order
+
+	for _, g := range d.Goals {
+		if indegree[g] > 0 {
+			d.Infinite = true
+		}
+	}
+}
+
+// DijkstraAll finds every cheapest path from start to goal on the grid
+func DijkstraAll(grid GridInterface, start, goal Coordinate) *ShortestPathDAG[Coordinate] {
+	return SearchDijkstraAll(start, isCoordinate(goal), gridSuccessors(grid))
+}
+
+// CountPaths returns the number of optimal paths, or -1 if there are infinitely
+// many. see CountPathsBig if this can overflow
+func (d *ShortestPathDAG[S]) CountPaths() int64 {
+	if d.Infinite {
+		return -1
+	}
+	counts := make(map[S]int64, len(d.order))
+	counts[d.Start] = 1
+	var total int64
+	for _, s := range d.order {
+		for _, p := range d.Preds[s] {
+			counts[s] += counts[p]
+		}
+	}
+	for _, g := range d.Goals {
+		total += counts[g]
+	}
+	return total
+}
+
+// CountPathsBig is CountPaths without overflow
+func (d *ShortestPathDAG[S]) CountPathsBig() *big.Int {
+	if d.Infinite {
+		return big.NewInt(-1)
+	}
+	counts := make(map[S]*big.Int, len(d.order))
+	for _, s := range d.order {
+		c := new(big.Int)
+		if s == d.Start {
+			c.SetInt64(1)
+		}
+		for _, p := range d.Preds[s] {
+			c.Add(c, counts[p])
+		}
+		counts[s] = c
+	}
+
+	total := new(big.Int)
+	for _, g := range d.Goals {
+		total.Add(total, counts[g])
+	}
+	return total
+}
+
+// OnPath returns the set of states that lie on at least one optimal path
+func (d *ShortestPathDAG[S]) OnPath() map[S]bool {
+	onPath := make(map[S]bool)
+	stack := Stack[S]{}
+	for _, g := range d.Goals {
+		onPath[g] = true
+		stack.Push(g)
+	}
+
+	for !stack.IsEmpty() {
+		s, _ := stack.Pop()
+		for _, p := range d.Preds[s] {
+			if !onPath[p] {
+				onPath[p] = true
+				stack.Push(p)
+			}
+		}
+	}
+	return onPath
+}
+
+// Paths lazily yields every optimal path in start -> goal order. each yielded
+// slice is freshly allocated. when Infinite is set it skips the paths that go
+// round a zero cost cycle
+func (d *ShortestPathDAG[S]) Paths() iter.Seq[[]S] {
+	return func(yield func([]S) bool) {
+		// path is built backwards from the goal
+		path := []S{}
+		onPath := make(map[S]bool)
+		var walk func(s S) bool
+		walk = func(s S) bool {
+			if onPath[s] {
+				return true
+			}
+			path = append(path, s)
+			onPath[s] = true
+			defer func() {
+				path = path[:len(path)-1]
+				delete(onPath, s)
+			}()
+
+			if s == d.Start {
+				out := slices.Clone(path)
+				slices.Reverse(out)
+				return yield(out)
+			}
+			for _, p := range d.Preds[s] {
+				if !walk(p) {
+					return false
+				}
+			}
+			return true
+		}
+
+		for _, g := range d.Goals {
+			if !walk(g) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package util
+
+// Bidirectional searches run one search forward from start using next and one
+// backward from goal using prev, which must return the predecessors of a state
+// with the cost of the edge into it. Visited counts expanded states on both
+// sides, so it compares directly with the single ended searches. Distance only
+// holds the forward distances
+
+// walks parent links from s until a self parent (the goal) and returns s..goal
+func followParents[S comparable](parent map[S]S, s S) []S {
+	path := []S{s}
+	for parent[s] != s {
+		s = parent[s]
+		path = append(path, s)
+	}
+	return path
+}
+
+// SearchBidirectionalBFS finds the path with the fewest moves. edge costs are ignored
+func SearchBidirectionalBFS[S comparable](start, goal S, next, prev SuccessorFunc[S]) SearchResult[S] {
+	if start == goal {
+		return SearchResult[S]{
+			Found:  true,
+			Source: start,
+			Path:   []S{start},
+			Cost:   0,
+		}
+	}
+
+	fwdDist := map[S]int{start: 0}
+	bwdDist := map[S]int{goal: 0}
+	fwdParent := map[S]S{start: start}
+	bwdParent := map[S]S{goal: goal}
+	fwdFrontier := []S{start}
+	bwdFrontier := []S{goal}
+	visitedCount := 0
+
+	for len(fwdFrontier) > 0 && len(bwdFrontier) > 0 {
+		// expand a whole level of the smaller side so the first meeting level is optimal
+		forward := len(fwdFrontier) <= len(bwdFrontier)
+		frontier, dist, parent, otherDist, step := fwdFrontier, fwdDist, fwdParent, bwdDist, next
+		if !forward {
+			frontier, dist, parent, otherDist, step = bwdFrontier, bwdDist, bwdParent, fwdDist, prev
+		}
+
+		best := -1
+		var from, to S
+		nextFrontier := []S{}
+		for _, current := range frontier {
+			visitedCount++
+			for _, e := range step(current) {
+				if _, seen := dist[e.To]; !seen {
+					dist[e.To] = dist[current] + 1
+					parent[e.To] = current
+					nextFrontier = append(nextFrontier, e.To)
+				}
+				if d, ok := otherDist[e.To]; ok && (best < 0 || dist[current]+1+d < best) {
+					best = dist[current] + 1 + d
+					from, to = current, e.To
+				}
+			}
+		}
+
+		if best >= 0 {
+			var path []S
+			if forward {
+				path = append(buildPath(fwdParent, from), followParents(bwdParent, to)...)
+			} else {
+				path = append(buildPath(fwdParent, to), followParents(bwdParent, from)...)
+			}
+			return SearchResult[S]{
+				Found:    true,
+				Source:   start,
+				Path:     path,
+				Cost:     best,
+				Visited:  visitedCount,
+				Distance: fwdDist,
+			}
+		}
+
+		if forward {
+			fwdFrontier = nextFrontier
+		} else {
+			bwdFrontier = nextFrontier
+		}
+	}
+
+	return SearchResult[S]{
+		Found:    false,
+		Visited:  visitedCount,
+		Distance: fwdDist,
+	}
+}
+
+// SearchBidirectionalDijkstra finds the cheapest path. negative edge costs are skipped
+func SearchBidirectionalDijkstra[S comparable](start, goal S, next, prev SuccessorFunc[S]) SearchResult[S] {
+	return SearchBidirectionalAStar(start, goal, next, prev, nil, nil)
+}
+
+// one direction of a bidirectional dijkstra/a*
+type bidiSide[S comparable] struct {
+	pq        *IndexedMinHeap[S, searchPriority]
+	g         map[S]int
+	parent    map[S]S
+	closed    map[S]bool
+	heuristic func(S) int
+	step      SuccessorFunc[S]
+}
+
+func newBidiSide[S comparable](from S, heuristic func(S) int, step SuccessorFunc[S]) *bidiSide[S] {
+	h := heuristic(from)
+	pq := NewIndexedMinHeapFunc[S](compareSearchPriority)
+	pq.Upsert(from, searchPriority{FCost: h, HCost: h})
+	return &bidiSide[S]{
+		pq:        pq,
+		g:         map[S]int{from: 0},
+		parent:    map[S]S{from: from},
+		closed:    make(map[S]bool),
+		heuristic: heuristic,
+		step:      step,
+	}
+}
+
+// SearchBidirectionalAStar is SearchBidirectionalDijkstra guided by toGoal for the
+// forward search and toStart for the backward one. both must be consistent. nil
+// heuristics behave like Dijkstra
+func SearchBidirectionalAStar[S comparable](start, goal S, next, prev SuccessorFunc[S], toGoal, toStart func(S) int) SearchResult[S] {
+	if start == goal {
+		return SearchResult[S]{
+			Found:  true,
+			Source: start,
+			Path:   []S{start},
+			Cost:   0,
+		}
+	}
+
+	// without heuristics the tighter sum of both queue tops can be used to stop
+	plain := toGoal == nil && toStart == nil
+	if toGoal == nil {
+		toGoal = func(S) int { return 0 }
+	}
+	if toStart == nil {
+		toStart = func(S) int { return 0 }
+	}
+
+	fwd := newBidiSide(start, toGoal, next)
+	bwd := newBidiSide(goal, toStart, prev)
+
+	// best is the cheapest complete path seen so far, meeting at meet
+	best := -1
+	var meet S
+	visitedCount := 0
+
+	for fwd.pq.Len() > 0 && bwd.pq.Len() > 0 {
+		if best >= 0 {
+			_, pf, _ := fwd.pq.PeekMin()
+			_, pb, _ := bwd.pq.PeekMin()
+			kf, kb := pf.FCost, pb.FCost
+			if max(kf, kb) >= best || (plain && kf+kb >= best) {
+				break
+			}
+		}
+
+		side, other := fwd, bwd
+		if bwd.pq.Len() < fwd.pq.Len() {
+			side, other = bwd, fwd
+		}
+
+		current, _ := side.pq.PopMin()
+		side.closed[current] = true
+		visitedCount++
+
+		for _, e := range side.step(current) {
+			if e.Cost < 0 || side.closed[e.To] {
+				continue
+			}
+
+			tentativeG := side.g[current] + e.Cost
+			if oldG, exists := side.g[e.To]; !exists || tentativeG < oldG {
+				side.g[e.To] = tentativeG
+				side.parent[e.To] = current
+
+				h := side.heuristic(e.To)
+				side.pq.Upsert(e.To, searchPriority{FCost: tentativeG + h, HCost: h})
+			}
+
+			if otherG, ok := other.g[e.To]; ok && (best < 0 || side.g[e.To]+otherG < best) {
+				best = side.g[e.To] + otherG
+				meet = e.To
+			}
+		}
+	}
+
+	if best < 0 {
+		return SearchResult[S]{
+			Found:    false,
+			Visited:  visitedCount,
+			Distance: fwd.g,
+		}
+	}
+
+	path := append(buildPath(fwd.parent, meet), followParents(bwd.parent, meet)[1:]...)
+	return SearchResult[S]{
+		Found:    true,
+		Source:   start,
+		Path:     path,
+		Cost:     best,
+		Visited:  visitedCount,
+		Distance: fwd.g,
+	}
+}
+
+// reverse of gridSuccessors. assumes neighbours are symmetric, which holds for
+// the default grids, and charges GetCost in the forward direction
+func gridPredecessors(grid GridInterface) SuccessorFunc[Coordinate] {
+	return func(pos Coordinate) []Edge[Coordinate] {
+		neighbors := grid.GetNeighbors(pos)
+		edges := make([]Edge[Coordinate], 0, len(neighbors))
+		for _, neighbor := range neighbors {
+			edges = append(edges, Edge[Coordinate]{To: neighbor, Cost: grid.GetCost(neighbor, pos)})
+		}
+		return edges
+	}
+}
+
+// BidirectionalBFS searches from both ends of the grid at once
+func BidirectionalBFS(grid GridInterface, start, goal Coordinate) PathResult {
+	return SearchBidirectionalBFS(start, goal, gridSuccessors(grid), gridPredecessors(grid))
+}
+
+// BidirectionalDijkstra searches from both ends of the grid at once
+func BidirectionalDijkstra(grid GridInterface, start, goal Coordinate) PathResult {
+	return SearchBidirectionalDijkstra(start, goal, gridSuccessors(grid), gridPredecessors(grid))
+}
+
+// BidirectionalAStar searches from both ends of the grid at once, using heuristic
+// towards goal going forward and towards start going backward
+func BidirectionalAStar(grid GridInterface, start, goal Coordinate, heuristic HeuristicFunc) PathResult {
+	if heuristic == nil {
+		heuristic = ManhattanHeuristic
+	}
+
+	return SearchBidirectionalAStar(start, goal, gridSuccessors(grid), gridPredecessors(grid),
+		func(pos Coordinate) int { return heuristic(pos, goal) },
+		func(pos Coordinate) int { return heuristic(pos, start) },
+	)
+}
@@ -0,0 +1,229 @@
+package util
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Compressed maps a set of coordinates onto 0..n-1 keeping their order
+type Compressed struct {
+	Values []int // sorted and unique
+	index  map[int]int
+}
+
+func Compress(xs []int) *Compressed {
+	values := slices.Clone(xs)
+	slices.Sort(values)
+	values = slices.Compact(values)
+
+	index := make(map[int]int, len(values))
+	for i, v := range values {
+		index[v] = i
+	}
+	return &Compressed{Values: values, index: index}
+}
+
+func (c *Compressed) Len() int { return len(c.Values) }
+
+// Index returns the compressed index of x, if x was one of the coordinates
+func (c *Compressed) Index(x int) (int, bool) {
+	i, ok := c.index[x]
+	return i, ok
+}
+
+// Value returns the original coordinate at compressed index i
+func (c *Compressed) Value(i int) int { return c.Values[i] }
+
+// one axis of a CompressedGrid. every coordinate gets a cell one tile wide and
+// every gap between neighbouring coordinates gets a single cell as wide as the
+// gap, so no tiles are lost
+type compressedAxis struct {
+	starts []int // first real coordinate in each cell
+	widths []int
+	cell   map[int]int // real coordinate -> its cell
+}
+
+func newCompressedAxis(c *Compressed) compressedAxis {
+	// a zero width cell either side so there's always a way around the outside
+	axis := compressedAxis{cell: make(map[int]int, c.Len())}
+	add := func(start, width int) {
+		axis.starts = append(axis.starts, start)
+		axis.widths = append(axis.widths, width)
+	}
+
+	if c.Len() == 0 {
+		return axis
+	}
+	add(c.Values[0], 0)
+	for i, v := range c.Values {
+		axis.cell[v] = len(axis.starts)
+		add(v, 1)
+		if i+1 < c.Len() && c.Values[i+1]-v > 1 {
+			add(v+1, c.Values[i+1]-v-1)
+		}
+	}
+	add(c.Values[c.Len()-1]+1, 0)
+	return axis
+}
+
+// CompressedGrid is a grid over huge sparse tile coordinates. only the listed xs
+// and ys get their own row or column and the runs of tiles between them are
+// squashed into single cells, so the grid stays tiny while every cell knows how
+// many real tiles it covers
+type CompressedGrid struct {
+	Xs, Ys *Compressed
+	Grid   *DenseGrid // one rune per compressed cell, '.' to begin with
+	xAxis  compressedAxis
+	yAxis  compressedAxis
+}
+
+func NewCompressedGrid(xs, ys []int) *CompressedGrid {
+	g := &CompressedGrid{Xs: Compress(xs), Ys: Compress(ys)}
+	g.xAxis = newCompressedAxis(g.Xs)
+	g.yAxis = newCompressedAxis(g.Ys)
+
+	width, height := len(g.xAxis.widths), len(g.yAxis.widths)
+	cells := make([][]rune, height)
+	for y := range cells {
+		cells[y] = make([]rune, width)
+		for x := range cells[y] {
+			cells[y][x] = '.'
+		}
+	}
+	g.Grid = NewDenseGrid(width, height, cells)
+	return g
+}
+
+// Cell returns the compressed cell holding the real tile p. p must sit on one of
+// the compressed xs and ys
+func (g *CompressedGrid) Cell(p Vec2) (Coordinate, bool) {
+	x, okX := g.xAxis.cell[p.X]
+	y, okY := g.yAxis.cell[p.Y]
+	return Coordinate{X: x, Y: y}, okX && okY
+}
+
+func (g *CompressedGrid) mustCell(p Vec2) Coordinate {
+	cell, ok := g.Cell(p)
+	if !ok {
+		panic(fmt.Sprintf("%v is not on a compressed coordinate", p))
+	}
+	return cell
+}
+
+// CellArea is the number of real tiles the cell covers
+func (g *CompressedGrid) CellArea(cell Coordinate) int {
+	return g.xAxis.widths[cell.X] * g.yAxis.widths[cell.Y]
+}
+
+// CellBounds returns the first and last real tiles the cell covers. the padding
+// cells around the edge cover nothing, so for them hi is before lo
+func (g *CompressedGrid) CellBounds(cell Coordinate) (lo, hi Vec2) {
+	lo = Vec2{X: g.xAxis.starts[cell.X], Y: g.yAxis.starts[cell.Y]}
+	hi = lo.Add(Vec2{X: g.xAxis.widths[cell.X] - 1, Y: g.yAxis.widths[cell.Y] - 1})
+	return lo, hi
+}
+
+// TraceLine sets every cell on the axis aligned line from a to b to r
+func (g *CompressedGrid) TraceLine(a, b Vec2, r rune) {
+	from, to := g.mustCell(a), g.mustCell(b)
+	lo, hi := from.Min(to), from.Max(to)
+	for y := lo.Y; y <= hi.Y; y++ {
+		for x := lo.X; x <= hi.X; x++ {
+			g.Grid.Grid[y][x] = r
+		}
+	}
+}
+
+// TracePolygon sets every cell on the outline of a rectilinear polygon to r
+func (g *CompressedGrid) TracePolygon(vertices []Vec2, r rune) {
+	for i, v := range vertices {
+		g.TraceLine(v, vertices[(i+1)%len(vertices)], r)
+	}
+}
+
+// FloodFill sets start and every cell joined to it holding the same rune to r,
+// returning how many real tiles changed
+func (g *CompressedGrid) FloodFill(start Coordinate, r rune) int {
+	target := g.Grid.Grid[start.Y][start.X]
+	if target == r {
+		return 0
+	}
+	same := NewDenseGridWithOptions(g.Grid.Width, g.Grid.Height, g.Grid.Grid, nil,
+		func(grid GridInterface, pos Coordinate) bool { return *grid.At(pos) == target })
+
+	area := 0
+	for cell := range FloodFill(same, start) {
+		g.Grid.Grid[cell.Y][cell.X] = r
+		area += g.CellArea(cell)
+	}
+	return area
+}
+
+// FillPolygon marks the outline of a rectilinear polygon and everything it
+// encloses as inside and every other cell as outside
+func (g *CompressedGrid) FillPolygon(vertices []Vec2, inside, outside rune) {
+	// with no xs or no ys there are no cells, so the polygon is degenerate and
+	// there's nothing to fill
+	if g.Grid.Width == 0 || g.Grid.Height == 0 {
+		return
+	}
+
+	const unknown = 0
+	for y := range g.Grid.Grid {
+		for x := range g.Grid.Grid[y] {
+			g.Grid.Grid[y][x] = unknown
+		}
+	}
+	g.TracePolygon(vertices, inside)
+
+	// the corner is padding so it's never on the outline
+	g.FloodFill(Coordinate{X: 0, Y: 0}, outside)
+	for y := range g.Grid.Grid {
+		for x := range g.Grid.Grid[y] {
+			if g.Grid.Grid[y][x] == unknown {
+				g.Grid.Grid[y][x] = inside
+			}
+		}
+	}
+}
+
+// Area counts the real tiles in cells holding r
+func (g *CompressedGrid) Area(r rune) int {
+	total := 0
+	for y, row := range g.Grid.Grid {
+		for x, cell := range row {
+			if cell == r {
+				total += g.CellArea(Coordinate{X: x, Y: y})
+			}
+		}
+	}
+	return total
+}
+
+// CompressedPrefix answers rectangle area queries on a CompressedGrid in O(1)
+type CompressedPrefix struct {
+	grid *CompressedGrid
+	sums *PrefixSum2D
+}
+
+// Prefix builds 2D prefix sums of the real tiles in cells matching match. it's a
+// snapshot, so changes to the grid afterwards aren't seen
+func (g *CompressedGrid) Prefix(match func(rune) bool) *CompressedPrefix {
+	areas := make([][]int, g.Grid.Height)
+	for y, row := range g.Grid.Grid {
+		areas[y] = make([]int, g.Grid.Width)
+		for x, cell := range row {
+			if match(cell) {
+				areas[y][x] = g.CellArea(Coordinate{X: x, Y: y})
+			}
+		}
+	}
+	return &CompressedPrefix{grid: g, sums: NewPrefixSum2D(areas, func(a int) int { return a })}
+}
+
+// RectArea counts the matching real tiles in the rectangle with opposite corners
+// a and b, inclusive. both must sit on compressed coordinates
+func (p *CompressedPrefix) RectArea(a, b Vec2) int {
+	from, to := p.grid.mustCell(a), p.grid.mustCell(b)
+	return p.sums.RectSum(from.X, from.Y, to.X, to.Y)
+}
@@ -0,0 +1,35 @@
+package util
+
+import "testing"
+
+func TestFillPolygon(t *testing.T) {
+	// an L shape: a 10x10 square with the top right 5x5 cut out
+	vertices := []Vec2{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 5}, {X: 9, Y: 5}, {X: 9, Y: 9}, {X: 0, Y: 9}}
+	xs, ys := []int{}, []int{}
+	for _, v := range vertices {
+		xs = append(xs, v.X)
+		ys = append(ys, v.Y)
+	}
+
+	g := NewCompressedGrid(xs, ys)
+	g.FillPolygon(vertices, '#', '.')
+	if got := g.Area('#'); got != 75 {
+		t.Errorf("inside area = %d, want 75", got)
+	}
+}
+
+func TestFillPolygonDegenerate(t *testing.T) {
+	for name, axes := range map[string][2][]int{
+		"no xs or ys": {nil, nil},
+		"no xs":       {nil, {1, 5}},
+		"no ys":       {{1, 5}, nil},
+	} {
+		t.Run(name, func(t *testing.T) {
+			g := NewCompressedGrid(axes[0], axes[1])
+			g.FillPolygon(nil, '#', '.')
+			if got := g.Area('#'); got != 0 {
+				t.Errorf("inside area = %d, want 0", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package util
+
+import (
+	"container/heap"
+	"slices"
+)
+
+// ready queue for TopoSort, ordered by cmp and then by when each node became
+// ready
+type cmpHeap[T any] struct {
+	items []cmpEntry[T]
+	cmp   func(a, b T) int
+	seq   int
+}
+
+type cmpEntry[T any] struct {
+	item T
+	seq  int
+}
+
+func (h cmpHeap[T]) Len() int { return len(h.items) }
+func (h cmpHeap[T]) Less(i, j int) bool {
+	if c := h.cmp(h.items[i].item, h.items[j].item); c != 0 {
+		return c < 0
+	}
+	return h.items[i].seq < h.items[j].seq
+}
+func (h cmpHeap[T]) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *cmpHeap[T]) Push(x any) {
+	h.items = append(h.items, cmpEntry[T]{item: x.(T), seq: h.seq})
+	h.seq++
+}
+
+func (h *cmpHeap[T]) Pop() any {
+	n := len(h.items)
+	entry := h.items[n-1]
+	h.items = h.items[:n-1]
+	return entry.item
+}
+
+// TopoSort orders the nodes of g so every edge points forward, using Kahn's
+// algorithm. when several nodes are ready the smallest by cmp goes first, so the
+// result is deterministic. a nil cmp, or a tie, takes them in the order they
+// became ready, starting from g.Nodes() order. that's only stable if g.Nodes() is,
+// which it isn't for an AdjList, so pass a cmp there. returns a *CycleError if g
+// has a cycle
+func TopoSort[N comparable](g NodeGraph[N], cmp func(a, b N) int) ([]N, error) {
+	if cmp == nil {
+		cmp = func(a, b N) int { return 0 }
+	}
+
+	nodes := g.Nodes()
+	indegree := make(map[N]int, len(nodes))
+	for _, n := range nodes {
+		if _, ok := indegree[n]; !ok {
+			indegree[n] = 0
+		}
+		for _, neigh := range g.Neighbors(n) {
+			indegree[neigh]++
+		}
+	}
+
+	// seeded from the nodes slice rather than the map so ties don't depend on map
+	// order
+	ready := &cmpHeap[N]{cmp: cmp}
+	seeded := make(map[N]bool)
+	for _, n := range nodes {
+		if indegree[n] == 0 && !seeded[n] {
+			seeded[n] = true
+			heap.Push(ready, n)
+		}
+	}
+
+	order := make([]N, 0, len(indegree))
+	for ready.Len() > 0 {
+		n := heap.Pop(ready).(N)
+		order = append(order, n)
+		for _, neigh := range g.Neighbors(n) {
+			indegree[neigh]--
+			if indegree[neigh] == 0 {
+				heap.Push(ready, neigh)
+			}
+		}
+	}
+
+	if len(order) < len(indegree) {
+		return nil, findCycle(g, indegree)
+	}
+	return order, nil
+}
+
+// every node kahn's couldn't remove still has an in-edge from another one, so
+// walking predecessors among them has to loop
+func findCycle[N comparable](g Graph[N], indegree map[N]int) *CycleError[N] {
+	preds := make(map[N]N)
+	var curr N
+	for n, deg := range indegree {
+		if deg == 0 {
+			continue
+		}
+		curr = n
+		for _, neigh := range g.Neighbors(n) {
+			if indegree[neigh] > 0 {
+				preds[neigh] = n
+			}
+		}
+	}
+
+	seenAt := make(map[N]int)
+	walk := []N{}
+	for {
+		if i, ok := seenAt[curr]; ok {
+			cycle := append(walk[i:], curr)
+			slices.Reverse(cycle)
+			return &CycleError[N]{Cycle: cycle}
+		}
+		seenAt[curr] = len(walk)
+		walk = append(walk, curr)
+		curr = preds[curr]
+	}
+}
+
+// StronglyConnectedComponents finds the SCCs of g with Tarjan's algorithm. the
+// components come out in reverse topological order: nothing in a component has an
+// edge to a component after it. which of several valid orders you get, and the
+// order inside each component, follows g.Nodes() and g.Neighbors(). those are
+// stable for a WeightedGraph but an AdjList lists its nodes in map order, so they
+// can change between runs
+func StronglyConnectedComponents[N comparable](g NodeGraph[N]) [][]N {
+	index := make(map[N]int)
+	low := make(map[N]int)
+	onStack := make(map[N]bool)
+	stack := []N{}
+	sccs := [][]N{}
+	nextIndex := 0
+
+	type frame struct {
+		node      N
+		neighbors []N
+		next      int
+	}
+
+	// iterative so long chains can't overflow the goroutine stack
+	for _, root := range g.Nodes() {
+		if _, ok := index[root]; ok {
+			continue
+		}
+
+		index[root], low[root] = nextIndex, nextIndex
+		nextIndex++
+		stack = append(stack, root)
+		onStack[root] = true
+		calls := []*frame{{node: root, neighbors: g.Neighbors(root)}}
+
+		for len(calls) > 0 {
+			f := calls[len(calls)-1]
+			if f.next < len(f.neighbors) {
+				w := f.neighbors[f.next]
+				f.next++
+				if _, ok := index[w]; !ok {
+					index[w], low[w] = nextIndex, nextIndex
+					nextIndex++
+					stack = append(stack, w)
+					onStack[w] = true
+					calls = append(calls, &frame{node: w, neighbors: g.Neighbors(w)})
+				} else if onStack[w] {
+					low[f.node] = min(low[f.node], index[w])
+				}
+				continue
+			}
+
+			calls = calls[:len(calls)-1]
+			if len(calls) > 0 {
+				parent := calls[len(calls)-1].node
+				low[parent] = min(low[parent], low[f.node])
+			}
+
+			// f is the root of a component, pop it off
+			if low[f.node] == index[f.node] {
+				scc := []N{}
+				for {
+					w := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[w] = false
+					scc = append(scc, w)
+					if w == f.node {
+						break
+					}
+				}
+				sccs = append(sccs, scc)
+			}
+		}
+	}
+
+	return sccs
+}
+
+// Condensation is g with every strongly connected component collapsed to one node
+type Condensation[N comparable] struct {
+	Components [][]N        // in topological order
+	Component  map[N]int    // index into Components for each node
+	DAG        AdjList[int] // edges between components, every component is a key
+}
+
+// Condense builds the condensation DAG of g. component order and ids come from
+// StronglyConnectedComponents, so they're only stable when g.Nodes() is
+func Condense[N comparable](g NodeGraph[N]) *Condensation[N] {
+	comps := StronglyConnectedComponents(g)
+	slices.Reverse(comps)
+
+	c := &Condensation[N]{
+		Components: comps,
+		Component:  make(map[N]int),
+		DAG:        make(AdjList[int], len(comps)),
+	}
+	for i, comp := range comps {
+		for _, n := range comp {
+			c.Component[n] = i
+		}
+	}
+
+	for i, comp := range comps {
+		seen := make(map[int]bool)
+		c.DAG[i] = []int{}
+		for _, n := range comp {
+			for _, neigh := range g.Neighbors(n) {
+				j := c.Component[neigh]
+				if j != i && !seen[j] {
+					seen[j] = true
+					c.DAG[i] = append(c.DAG[i], j)
+				}
+			}
+		}
+	}
+
+	return c
+}
+
+// DAGShortestPath finds the cheapest path from start to end in a DAG in linear
+// time. weight can be negative. a nil weight counts every edge as 1
+func DAGShortestPath[N comparable](g NodeGraph[N], start, end N, weight func(from, to N) int) (SearchResult[N], error) {
+	return dagPath(g, start, end, weight, func(a, b int) bool { return a < b })
+}
+
+// DAGLongestPath is DAGShortestPath but finds the most expensive path
+func DAGLongestPath[N comparable](g NodeGraph[N], start, end N, weight func(from, to N) int) (SearchResult[N], error) {
+	return dagPath(g, start, end, weight, func(a, b int) bool { return a > b })
+}
+
+func dagPath[N comparable](g NodeGraph[N], start, end N, weight func(from, to N) int, better func(a, b int) bool) (SearchResult[N], error) {
+	order, err := TopoSort(g, nil)
+	if err != nil {
+		return SearchResult[N]{}, err
+	}
+	if weight == nil {
+		weight = func(from, to N) int { return 1 }
+	}
+
+	distance := map[N]int{start: 0}
+	parent := map[N]N{start: start}
+	for _, u := range order {
+		du, ok := distance[u]
+		if !ok {
+			continue // not reachable from start
+		}
+		for _, v := range g.Neighbors(u) {
+			newDist := du + weight(u, v)
+			if old, exists := distance[v]; !exists || better(newDist, old) {
+				distance[v] = newDist
+				parent[v] = u
+			}
+		}
+	}
+
+	if _, ok := distance[end]; !ok {
+		return SearchResult[N]{
+			Found:    false,
+			Visited:  len(distance),
+			Distance: distance,
+		}, nil
+	}
+
+	return SearchResult[N]{
+		Found:    true,
+		Source:   start,
+		Path:     buildPath(parent, end),
+		Cost:     distance[end],
+		Visited:  len(distance),
+		Distance: distance,
+	}, nil
+}
@@ -0,0 +1,141 @@
+package util
+
+import "iter"
+
+// Deque is a double ended queue on a growable ring buffer. the zero value is an
+// empty deque ready to use
+type Deque[T any] struct {
+	buf  []T
+	head int // index in buf of the front item
+	size int
+}
+
+// NewDeque returns a deque holding items, front first
+func NewDeque[T any](items ...T) *Deque[T] {
+	d := &Deque[T]{buf: make([]T, max(len(items), 8))}
+	copy(d.buf, items)
+	d.size = len(items)
+	return d
+}
+
+func (d *Deque[T]) Len() int { return d.size }
+
+func (d *Deque[T]) IsEmpty() bool { return d.size == 0 }
+
+// index in buf of the i'th item from the front
+func (d *Deque[T]) slot(i int) int {
+	return (d.head + i) % len(d.buf)
+}
+
+// doubles the buffer, unrolling the items to the start of it
+func (d *Deque[T]) grow() {
+	buf := make([]T, max(2*len(d.buf), 8))
+	n := copy(buf, d.buf[d.head:min(d.head+d.size, len(d.buf))])
+	copy(buf[n:], d.buf[:d.size-n])
+	d.buf = buf
+	d.head = 0
+}
+
+func (d *Deque[T]) PushBack(item T) {
+	if d.size == len(d.buf) {
+		d.grow()
+	}
+	d.buf[d.slot(d.size)] = item
+	d.size++
+}
+
+func (d *Deque[T]) PushFront(item T) {
+	if d.size == len(d.buf) {
+		d.grow()
+	}
+	d.head = (d.head - 1 + len(d.buf)) % len(d.buf)
+	d.buf[d.head] = item
+	d.size++
+}
+
+func (d *Deque[T]) PopFront() (T, bool) {
+	var zero T
+	if d.size == 0 {
+		return zero, false
+	}
+	item := d.buf[d.head]
+	d.buf[d.head] = zero // don't hold on to anything the item points at
+	d.head = d.slot(1)
+	d.size--
+	return item, true
+}
+
+func (d *Deque[T]) PopBack() (T, bool) {
+	var zero T
+	if d.size == 0 {
+		return zero, false
+	}
+	i := d.slot(d.size - 1)
+	item := d.buf[i]
+	d.buf[i] = zero
+	d.size--
+	return item, true
+}
+
+func (d *Deque[T]) PeekFront() (T, bool) {
+	if d.size == 0 {
+		var zero T
+		return zero, false
+	}
+	return d.buf[d.head], true
+}
+
+func (d *Deque[T]) PeekBack() (T, bool) {
+	if d.size == 0 {
+		var zero T
+		return zero, false
+	}
+	return d.buf[d.slot(d.size-1)], true
+}
+
+// At returns the i'th item from the front. it panics if i is out of range
+func (d *Deque[T]) At(i int) T {
+	if i < 0 || i >= d.size {
+		panic("deque index out of range")
+	}
+	return d.buf[d.slot(i)]
+}
+
+// Rotate moves the last n items round to the front, or the first -n items round
+// to the back when n is negative
+func (d *Deque[T]) Rotate(n int) {
+	if d.size <= 1 {
+		return
+	}
+	n = mod(n, d.size)
+	if n == 0 {
+		return
+	}
+	if d.size == len(d.buf) {
+		// full, so moving the head is the whole rotation
+		d.head = (d.head - n + len(d.buf)) % len(d.buf)
+		return
+	}
+	if n <= d.size/2 {
+		for range n {
+			item, _ := d.PopBack()
+			d.PushFront(item)
+		}
+		return
+	}
+	for range d.size - n {
+		item, _ := d.PopFront()
+		d.PushBack(item)
+	}
+}
+
+// All iterates front to back
+func (d *Deque[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := range d.size {
+			if !yield(i, d.buf[d.slot(i)]) {
+				return
+			}
+		}
+	}
+}
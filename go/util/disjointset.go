@@ -0,0 +1,104 @@
+package util
+
+// DisjointSet is a union-find over arbitrary keys with path compression and
+// union by size
+type DisjointSet[T comparable] struct {
+	parent     map[T]T
+	size       map[T]int
+	order      []T // insertion order, keeps Components deterministic
+	components int
+}
+
+func NewDisjointSet[T comparable](items ...T) *DisjointSet[T] {
+	d := &DisjointSet[T]{
+		parent: make(map[T]T, len(items)),
+		size:   make(map[T]int, len(items)),
+	}
+	for _, item := range items {
+		d.Add(item)
+	}
+	return d
+}
+
+// Add puts x in a set of its own. adding an existing item does nothing
+func (d *DisjointSet[T]) Add(x T) {
+	if _, ok := d.parent[x]; ok {
+		return
+	}
+	d.parent[x] = x
+	d.size[x] = 1
+	d.order = append(d.order, x)
+	d.components++
+}
+
+// Find returns the representative of the set holding x, adding x if it's new
+func (d *DisjointSet[T]) Find(x T) T {
+	d.Add(x)
+
+	root := x
+	for d.parent[root] != root {
+		root = d.parent[root]
+	}
+
+	// point everything on the way straight at the root
+	for x != root {
+		next := d.parent[x]
+		d.parent[x] = root
+		x = next
+	}
+	return root
+}
+
+// Union merges the sets holding a and b. returns false if they were already together
+func (d *DisjointSet[T]) Union(a, b T) bool {
+	ra, rb := d.Find(a), d.Find(b)
+	if ra == rb {
+		return false
+	}
+
+	// hang the smaller tree off the bigger one
+	if d.size[ra] < d.size[rb] {
+		ra, rb = rb, ra
+	}
+	d.parent[rb] = ra
+	d.size[ra] += d.size[rb]
+	delete(d.size, rb)
+	d.components--
+	return true
+}
+
+func (d *DisjointSet[T]) Connected(a, b T) bool {
+	return d.Find(a) == d.Find(b)
+}
+
+// Size returns the number of items in the set holding x
+func (d *DisjointSet[T]) Size(x T) int {
+	return d.size[d.Find(x)]
+}
+
+// NumComponents returns the number of disjoint sets
+func (d *DisjointSet[T]) NumComponents() int {
+	return d.components
+}
+
+// Len returns the number of items across all sets
+func (d *DisjointSet[T]) Len() int {
+	return len(d.parent)
+}
+
+// Components groups the items by set. sets and their items are in insertion order
+func (d *DisjointSet[T]) Components() [][]T {
+	index := make(map[T]int, d.components)
+	groups := make([][]T, 0, d.components)
+	for _, x := range d.order {
+		root := d.Find(x)
+		i, ok := index[root]
+		if !ok {
+			i = len(groups)
+			index[root] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], x)
+	}
+	return groups
+}
@@ -0,0 +1,178 @@
+package util
+
+import (
+	"fmt"
+	"sort"
+)
+
+// FenwickValue is what a Fenwick tree can sum
+type FenwickValue interface {
+	~int | ~int64
+}
+
+// Fenwick is a binary indexed tree over indexes 0..n-1 with point updates and
+// prefix sums in O(log n). ranges are half open like Interval
+type Fenwick[T FenwickValue] struct {
+	tree []T // 1 based internally
+}
+
+func NewFenwick[T FenwickValue](n int) *Fenwick[T] {
+	return &Fenwick[T]{tree: make([]T, n+1)}
+}
+
+// NewFenwickFrom builds a tree holding values in O(n)
+func NewFenwickFrom[T FenwickValue](values []T) *Fenwick[T] {
+	f := NewFenwick[T](len(values))
+	copy(f.tree[1:], values)
+	for i := 1; i < len(f.tree); i++ {
+		if parent := i + i&-i; parent < len(f.tree) {
+			f.tree[parent] += f.tree[i]
+		}
+	}
+	return f
+}
+
+func (f *Fenwick[T]) Len() int { return len(f.tree) - 1 }
+
+// Add adds delta to index i. it panics if i is out of range
+func (f *Fenwick[T]) Add(i int, delta T) {
+	if i < 0 || i >= f.Len() {
+		panic(fmt.Sprintf("fenwick index %d out of range [0, %d)", i, f.Len()))
+	}
+	for i++; i < len(f.tree); i += i & -i {
+		f.tree[i] += delta
+	}
+}
+
+// PrefixSum is the total of indexes [0, i)
+func (f *Fenwick[T]) PrefixSum(i int) T {
+	var total T
+	for i = min(i, f.Len()); i > 0; i -= i & -i {
+		total += f.tree[i]
+	}
+	return total
+}
+
+// RangeSum is the total of indexes [l, r)
+func (f *Fenwick[T]) RangeSum(l, r int) T {
+	if l >= r {
+		return 0
+	}
+	return f.PrefixSum(r) - f.PrefixSum(l)
+}
+
+// Get is the value at index i
+func (f *Fenwick[T]) Get(i int) T {
+	return f.RangeSum(i, i+1)
+}
+
+// LowerBound returns the smallest i where PrefixSum(i+1) >= sum, or Len() if
+// there isn't one. only valid when no value is negative
+func (f *Fenwick[T]) LowerBound(sum T) int {
+	if sum <= 0 {
+		return 0
+	}
+	pos := 0
+	step := 1
+	for step*2 < len(f.tree) {
+		step *= 2
+	}
+	for ; step > 0; step /= 2 {
+		if next := pos + step; next < len(f.tree) && f.tree[next] < sum {
+			pos = next
+			sum -= f.tree[next]
+		}
+	}
+	return pos
+}
+
+// Fenwick2D is a Fenwick tree over a width x height grid, for counting points in
+// rectangles while they're being added
+type Fenwick2D[T FenwickValue] struct {
+	Width, Height int
+	tree          [][]T // 1 based internally
+}
+
+func NewFenwick2D[T FenwickValue](width, height int) *Fenwick2D[T] {
+	tree := make([][]T, height+1)
+	for y := range tree {
+		tree[y] = make([]T, width+1)
+	}
+	return &Fenwick2D[T]{Width: width, Height: height, tree: tree}
+}
+
+// Add adds delta to the cell (x, y). it panics if the cell is off the grid
+func (f *Fenwick2D[T]) Add(x, y int, delta T) {
+	if x < 0 || x >= f.Width || y < 0 || y >= f.Height {
+		panic(fmt.Sprintf("fenwick cell (%d, %d) out of range for %dx%d", x, y, f.Width, f.Height))
+	}
+	for j := y + 1; j <= f.Height; j += j & -j {
+		for i := x + 1; i <= f.Width; i += i & -i {
+			f.tree[j][i] += delta
+		}
+	}
+}
+
+// PrefixSum is the total of cells with x' < x and y' < y
+func (f *Fenwick2D[T]) PrefixSum(x, y int) T {
+	var total T
+	for j := min(y, f.Height); j > 0; j -= j & -j {
+		for i := min(x, f.Width); i > 0; i -= i & -i {
+			total += f.tree[j][i]
+		}
+	}
+	return total
+}
+
+// RectSum is the total of cells in [x0, x1) x [y0, y1)
+func (f *Fenwick2D[T]) RectSum(x0, y0, x1, y1 int) T {
+	if x0 >= x1 || y0 >= y1 {
+		return 0
+	}
+	return f.PrefixSum(x1, y1) - f.PrefixSum(x0, y1) - f.PrefixSum(x1, y0) + f.PrefixSum(x0, y0)
+}
+
+// CompressedFenwick is a Fenwick tree keyed by arbitrary, possibly huge ints. the
+// keys that will be updated have to be known up front, but queries can use any
+// key
+type CompressedFenwick[T FenwickValue] struct {
+	Keys *Compressed
+	tree *Fenwick[T]
+}
+
+func NewCompressedFenwick[T FenwickValue](keys []int) *CompressedFenwick[T] {
+	c := Compress(keys)
+	return &CompressedFenwick[T]{Keys: c, tree: NewFenwick[T](c.Len())}
+}
+
+// Add adds delta at key, which must be one of the keys the tree was built with
+func (f *CompressedFenwick[T]) Add(key int, delta T) {
+	i, ok := f.Keys.Index(key)
+	if !ok {
+		panic(fmt.Sprintf("key %d was not given to NewCompressedFenwick", key))
+	}
+	f.tree.Add(i, delta)
+}
+
+// PrefixSum is the total at keys below key
+func (f *CompressedFenwick[T]) PrefixSum(key int) T {
+	return f.tree.PrefixSum(sort.SearchInts(f.Keys.Values, key))
+}
+
+// RangeSum is the total at keys in [lo, hi)
+func (f *CompressedFenwick[T]) RangeSum(lo, hi int) T {
+	if lo >= hi {
+		return 0
+	}
+	return f.PrefixSum(hi) - f.PrefixSum(lo)
+}
+
+// LowerBound returns the smallest key whose running total reaches sum, and false
+// if the whole tree doesn't reach it. only valid when no value is negative
+func (f *CompressedFenwick[T]) LowerBound(sum T) (int, bool) {
+	i := f.tree.LowerBound(sum)
+	if i >= f.Keys.Len() {
+		return 0, false
+	}
+	return f.Keys.Value(i), true
+}
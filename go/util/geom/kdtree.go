@@ -0,0 +1,241 @@
+package geom
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+
+	"github.com/smort/aoc2025/util"
+)
+
+// Neighbor is a point found by a query along with its index in the points the
+// tree was built from
+type Neighbor[P any, T Scalar] struct {
+	Point  P
+	Index  int
+	DistSq T
+}
+
+// ordered by distance then index so ties always come out the same way
+func closer[P any, T Scalar](a, b Neighbor[P, T]) bool {
+	if a.DistSq != b.DistSq {
+		return a.DistSq < b.DistSq
+	}
+	return a.Index < b.Index
+}
+
+// KDTree indexes points for nearest neighbour queries. the tree is implicit: each
+// range of points is split at its median on an axis that cycles with depth.
+// integer points go in as util.Vec2 or util.Vec3, e.g. NewKDTree[util.Vec3, int]
+type KDTree[P Point[T], T Scalar] struct {
+	points []P
+	index  []int // original index of each entry in points
+	dims   int
+}
+
+func NewKDTree[P Point[T], T Scalar](points []P) *KDTree[P, T] {
+	t := &KDTree[P, T]{
+		points: slices.Clone(points),
+		index:  make([]int, len(points)),
+	}
+	for i := range t.index {
+		t.index[i] = i
+	}
+	if len(points) > 0 {
+		t.dims = points[0].Dims()
+	}
+	t.build(0, len(points), 0)
+	return t
+}
+
+func New2D[T Float](points []Point2[T]) *KDTree[Point2[T], T] {
+	return NewKDTree[Point2[T], T](points)
+}
+
+func New3D[T Float](points []Point3[T]) *KDTree[Point3[T], T] {
+	return NewKDTree[Point3[T], T](points)
+}
+
+// sorts [lo, hi) on the axis for depth and recurses either side of the median
+func (t *KDTree[P, T]) build(lo, hi, depth int) {
+	if hi-lo <= 1 {
+		return
+	}
+	axis := depth % t.dims
+
+	order := make([]int, hi-lo)
+	for i := range order {
+		order[i] = lo + i
+	}
+	slices.SortFunc(order, func(a, b int) int {
+		if c := cmp.Compare(t.points[a].Axis(axis), t.points[b].Axis(axis)); c != 0 {
+			return c
+		}
+		return cmp.Compare(t.index[a], t.index[b])
+	})
+
+	points := make([]P, len(order))
+	index := make([]int, len(order))
+	for i, o := range order {
+		points[i], index[i] = t.points[o], t.index[o]
+	}
+	copy(t.points[lo:hi], points)
+	copy(t.index[lo:hi], index)
+
+	mid := (lo + hi) / 2
+	t.build(lo, mid, depth+1)
+	t.build(mid+1, hi, depth+1)
+}
+
+func (t *KDTree[P, T]) Len() int { return len(t.points) }
+
+// Nearest returns the closest point to q
+func (t *KDTree[P, T]) Nearest(q P) (Neighbor[P, T], bool) {
+	found := t.KNearest(q, 1)
+	if len(found) == 0 {
+		return Neighbor[P, T]{}, false
+	}
+	return found[0], true
+}
+
+// KNearest returns the k closest points to q, closest first
+func (t *KDTree[P, T]) KNearest(q P, k int) []Neighbor[P, T] {
+	return t.kNearest(q, k, nil)
+}
+
+// kNearest only considers points whose original index passes keep
+func (t *KDTree[P, T]) kNearest(q P, k int, keep func(int) bool) []Neighbor[P, T] {
+	best := util.NewTopK(k, closer[P, T])
+	var search func(lo, hi, depth int)
+	search = func(lo, hi, depth int) {
+		if lo >= hi {
+			return
+		}
+		mid := (lo + hi) / 2
+		if keep == nil || keep(t.index[mid]) {
+			best.Add(Neighbor[P, T]{Point: t.points[mid], Index: t.index[mid], DistSq: DistSq[P, T](q, t.points[mid])})
+		}
+
+		axis := depth % t.dims
+		diff := q.Axis(axis) - t.points[mid].Axis(axis)
+		near, far := [2]int{lo, mid}, [2]int{mid + 1, hi}
+		if diff > 0 {
+			near, far = far, near
+		}
+
+		search(near[0], near[1], depth+1)
+		// only cross the split if something over there could still make the cut
+		worst, ok := best.Worst()
+		if best.Len() < k || !ok || diff*diff <= worst.DistSq {
+			search(far[0], far[1], depth+1)
+		}
+	}
+	search(0, len(t.points), 0)
+	return best.Items()
+}
+
+// WithinRadius returns every point no further than r from q, closest first
+func (t *KDTree[P, T]) WithinRadius(q P, r T) []Neighbor[P, T] {
+	rSq := r * r
+	found := []Neighbor[P, T]{}
+	var search func(lo, hi, depth int)
+	search = func(lo, hi, depth int) {
+		if lo >= hi {
+			return
+		}
+		mid := (lo + hi) / 2
+		if d := DistSq[P, T](q, t.points[mid]); d <= rSq {
+			found = append(found, Neighbor[P, T]{Point: t.points[mid], Index: t.index[mid], DistSq: d})
+		}
+
+		axis := depth % t.dims
+		diff := q.Axis(axis) - t.points[mid].Axis(axis)
+		if diff <= 0 || diff*diff <= rSq {
+			search(lo, mid, depth+1)
+		}
+		if diff >= 0 || diff*diff <= rSq {
+			search(mid+1, hi, depth+1)
+		}
+	}
+	search(0, len(t.points), 0)
+
+	slices.SortFunc(found, func(a, b Neighbor[P, T]) int {
+		if closer(a, b) {
+			return -1
+		}
+		if closer(b, a) {
+			return 1
+		}
+		return 0
+	})
+	return found
+}
+
+// PointPair is two points by their original index, I < J
+type PointPair[T Scalar] struct {
+	I, J   int
+	DistSq T
+}
+
+func comparePairs[T Scalar](a, b PointPair[T]) int {
+	if c := cmp.Compare(a.DistSq, b.DistSq); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.I, b.I); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.J, b.J)
+}
+
+// per point buffer of its next closest partners with a higher index
+type pairCursor[P any, T Scalar] struct {
+	neighbors []Neighbor[P, T]
+	next      int
+	fetched   int // k used for the last fetch
+}
+
+// ClosestPairs yields every pair of points closest first, ties by index, without
+// building all n² pairs up front. each point keeps a small buffer of its nearest
+// higher indexed partners that doubles in size when it runs out
+func (t *KDTree[P, T]) ClosestPairs() iter.Seq[PointPair[T]] {
+	return func(yield func(PointPair[T]) bool) {
+		n := len(t.points)
+		pointAt := make([]P, n)
+		for i, idx := range t.index {
+			pointAt[idx] = t.points[i]
+		}
+
+		cursors := make([]*pairCursor[P, T], n)
+		pq := util.NewMinHeapFunc[int](comparePairs[T])
+
+		// queues up the next partner for i, refilling its buffer if needed
+		advance := func(i int) {
+			c := cursors[i]
+			if c.next == len(c.neighbors) {
+				if c.fetched > len(c.neighbors) {
+					return // already has every partner
+				}
+				c.fetched = max(4, c.fetched*2)
+				c.neighbors = t.kNearest(pointAt[i], c.fetched, func(j int) bool { return j > i })
+			}
+			if c.next < len(c.neighbors) {
+				nb := c.neighbors[c.next]
+				c.next++
+				pq.Push(i, PointPair[T]{I: i, J: nb.Index, DistSq: nb.DistSq})
+			}
+		}
+
+		for i := range n {
+			cursors[i] = &pairCursor[P, T]{}
+			advance(i)
+		}
+
+		for pq.Len() > 0 {
+			item := pq.PopItem()
+			if !yield(item.Priority) {
+				return
+			}
+			advance(item.Value)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package geom
+
+// Scalar is any numeric coordinate type
+type Scalar interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~float32 | ~float64
+}
+
+// Float is the coordinate type of Point2 and Point3. integer points should use
+// util.Vec2 and util.Vec3, which also satisfy Point
+type Float interface {
+	~float32 | ~float64
+}
+
+// Point is anything with a fixed number of coordinates
+type Point[T Scalar] interface {
+	comparable
+	Dims() int
+	Axis(i int) T
+}
+
+// Point2 is a 2D point with float coordinates
+type Point2[T Float] struct {
+	X, Y T
+}
+
+func (p Point2[T]) Dims() int { return 2 }
+
+func (p Point2[T]) Axis(i int) T {
+	if i == 0 {
+		return p.X
+	}
+	return p.Y
+}
+
+// Point3 is a 3D point with float coordinates
+type Point3[T Float] struct {
+	X, Y, Z T
+}
+
+func (p Point3[T]) Dims() int { return 3 }
+
+func (p Point3[T]) Axis(i int) T {
+	switch i {
+	case 0:
+		return p.X
+	case 1:
+		return p.Y
+	}
+	return p.Z
+}
+
+// DistSq is the squared euclidean distance. it stays exact for integer points
+func DistSq[P Point[T], T Scalar](a, b P) T {
+	var total T
+	for i := range a.Dims() {
+		d := a.Axis(i) - b.Axis(i)
+		total += d * d
+	}
+	return total
+}
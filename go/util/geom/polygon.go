@@ -0,0 +1,221 @@
+package geom
+
+import "github.com/smort/aoc2025/util"
+
+// Polygon is a simple polygon on integer coordinates. the last vertex joins back
+// to the first. everything here works in exact integer maths
+type Polygon struct {
+	Vertices []util.Vec2
+}
+
+func NewPolygon(vertices []util.Vec2) *Polygon {
+	return &Polygon{Vertices: vertices}
+}
+
+// edges calls f with each edge in order, including the closing one
+func (p *Polygon) edges(f func(a, b util.Vec2) bool) {
+	n := len(p.Vertices)
+	for i := range n {
+		if !f(p.Vertices[i], p.Vertices[(i+1)%n]) {
+			return
+		}
+	}
+}
+
+// SignedArea2 is twice the shoelace area, positive when the vertices run
+// clockwise on screen (y down)
+func (p *Polygon) SignedArea2() int {
+	total := 0
+	p.edges(func(a, b util.Vec2) bool {
+		total += a.Cross(b)
+		return true
+	})
+	return total
+}
+
+// Area2 is twice the enclosed area. it's always an integer, the area itself
+// might not be
+func (p *Polygon) Area2() int {
+	return max(p.SignedArea2(), -p.SignedArea2())
+}
+
+// Area is the enclosed area, rounded down if the polygon isn't rectilinear
+func (p *Polygon) Area() int {
+	return p.Area2() / 2
+}
+
+// BoundaryPoints counts lattice points on the edges
+func (p *Polygon) BoundaryPoints() int {
+	total := 0
+	p.edges(func(a, b util.Vec2) bool {
+		d := b.Sub(a).Abs()
+		total += gcd(d.X, d.Y)
+		return true
+	})
+	return total
+}
+
+// InteriorPoints counts lattice points strictly inside, from Pick's theorem
+// A = I + B/2 - 1
+func (p *Polygon) InteriorPoints() int {
+	return (p.Area2() - p.BoundaryPoints() + 2) / 2
+}
+
+// LatticePoints counts lattice points inside or on the boundary. on a tile grid
+// where the outline runs through tile centres this is the number of tiles covered
+func (p *Polygon) LatticePoints() int {
+	return p.InteriorPoints() + p.BoundaryPoints()
+}
+
+// Contains reports whether q is inside the polygon or on its boundary
+func (p *Polygon) Contains(q util.Vec2) bool {
+	return p.containsDoubled(q.Scale(2))
+}
+
+// containsDoubled is Contains for a point given at twice its coordinates, so
+// half way points like the centre of a rectangle stay integers
+func (p *Polygon) containsDoubled(q util.Vec2) bool {
+	inside := false
+	onEdge := false
+	p.edges(func(a, b util.Vec2) bool {
+		a, b = a.Scale(2), b.Scale(2)
+		if onSegment(a, b, q) {
+			onEdge = true
+			return false
+		}
+		// cast a ray towards +x and count the edges it crosses. half open in y so
+		// a vertex on the ray is only counted once
+		if (a.Y > q.Y) != (b.Y > q.Y) {
+			if (b.Sub(a).Cross(q.Sub(a)) > 0) == (b.Y > a.Y) {
+				inside = !inside
+			}
+		}
+		return true
+	})
+	return onEdge || inside
+}
+
+// ContainsRect reports whether the axis aligned rectangle with opposite corners
+// a and b lies entirely inside the polygon, boundary included. the polygon must
+// be rectilinear
+func (p *Polygon) ContainsRect(a, b util.Vec2) bool {
+	lo, hi := a.Min(b), a.Max(b)
+	if lo.X == hi.X || lo.Y == hi.Y {
+		return p.containsSegment(lo, hi)
+	}
+
+	// an edge through the open interior means part of the rectangle is outside.
+	// with none the interior is all in or all out, so the centre decides
+	crossed := false
+	p.edges(func(e1, e2 util.Vec2) bool {
+		elo, ehi := e1.Min(e2), e1.Max(e2)
+		if elo.X < hi.X && ehi.X > lo.X && elo.Y < hi.Y && ehi.Y > lo.Y {
+			crossed = true
+			return false
+		}
+		return true
+	})
+	if crossed {
+		return false
+	}
+	return p.containsDoubled(lo.Add(hi))
+}
+
+// containsSegment checks an axis aligned segment from lo to hi. it's split at
+// every vertex coordinate along it, and each piece between is then entirely
+// inside, outside or on the boundary, so its midpoint decides
+func (p *Polygon) containsSegment(lo, hi util.Vec2) bool {
+	if !p.Contains(lo) || !p.Contains(hi) {
+		return false
+	}
+	axis := 0
+	if lo.X == hi.X {
+		axis = 1
+	}
+
+	cuts := []int{lo.Axis(axis), hi.Axis(axis)}
+	for _, v := range p.Vertices {
+		if c := v.Axis(axis); c > lo.Axis(axis) && c < hi.Axis(axis) {
+			cuts = append(cuts, c)
+		}
+	}
+	for _, c := range cuts {
+		point := lo
+		if axis == 0 {
+			point.X = c
+		} else {
+			point.Y = c
+		}
+		if !p.Contains(point) {
+			return false
+		}
+	}
+
+	for _, c1 := range cuts {
+		// the next cut along from c1, if there is one
+		c2 := hi.Axis(axis)
+		for _, c := range cuts {
+			if c > c1 && c < c2 {
+				c2 = c
+			}
+		}
+		if c2 == c1 {
+			continue
+		}
+		mid := lo.Scale(2)
+		if axis == 0 {
+			mid.X = c1 + c2
+		} else {
+			mid.Y = c1 + c2
+		}
+		if !p.containsDoubled(mid) {
+			return false
+		}
+	}
+	return true
+}
+
+// SegmentsIntersect reports whether the closed segments a1-a2 and b1-b2 share a
+// point, including touching at an end or overlapping along a line
+func SegmentsIntersect(a1, a2, b1, b2 util.Vec2) bool {
+	d1 := orientation(b1, b2, a1)
+	d2 := orientation(b1, b2, a2)
+	d3 := orientation(a1, a2, b1)
+	d4 := orientation(a1, a2, b2)
+
+	if d1*d2 < 0 && d3*d4 < 0 {
+		return true
+	}
+	return (d1 == 0 && onSegment(b1, b2, a1)) ||
+		(d2 == 0 && onSegment(b1, b2, a2)) ||
+		(d3 == 0 && onSegment(a1, a2, b1)) ||
+		(d4 == 0 && onSegment(a1, a2, b2))
+}
+
+// sign of the turn a -> b -> c
+func orientation(a, b, c util.Vec2) int {
+	cross := b.Sub(a).Cross(c.Sub(a))
+	switch {
+	case cross > 0:
+		return 1
+	case cross < 0:
+		return -1
+	}
+	return 0
+}
+
+// onSegment reports whether q lies on the closed segment a-b
+func onSegment(a, b, q util.Vec2) bool {
+	if orientation(a, b, q) != 0 {
+		return false
+	}
+	lo, hi := a.Min(b), a.Max(b)
+	return q.X >= lo.X && q.X <= hi.X && q.Y >= lo.Y && q.Y <= hi.Y
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -0,0 +1,229 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+type Graph[N comparable] interface {
+	Neighbors(v N) []N
+}
+
+type AdjList[K comparable] map[K][]K
+
+func (a AdjList[K]) Neighbors(v K) []K {
+	return a[v]
+}
+
+// NodeGraph is a Graph that can also list all of its nodes
+type NodeGraph[N comparable] interface {
+	Graph[N]
+	Nodes() []N
+}
+
+// Nodes returns every node in the list, including sinks that only show up as
+// neighbours. the order is not stable
+func (a AdjList[K]) Nodes() []K {
+	seen := make(map[K]bool, len(a))
+	nodes := make([]K, 0, len(a))
+	add := func(n K) {
+		if !seen[n] {
+			seen[n] = true
+			nodes = append(nodes, n)
+		}
+	}
+	for k, neighbors := range a {
+		add(k)
+		for _, n := range neighbors {
+			add(n)
+		}
+	}
+	return nodes
+}
+
+// Reverse returns a copy of the list with every edge flipped
+func (a AdjList[K]) Reverse() AdjList[K] {
+	return Reverse[K](a)
+}
+
+// Reverse builds an AdjList with every edge of g flipped. every node of g is a key
+func Reverse[N comparable](g NodeGraph[N]) AdjList[N] {
+	nodes := g.Nodes()
+	rev := make(AdjList[N], len(nodes))
+	for _, n := range nodes {
+		if _, ok := rev[n]; !ok {
+			rev[n] = nil
+		}
+		for _, neigh := range g.Neighbors(n) {
+			rev[neigh] = append(rev[neigh], n)
+		}
+	}
+	return rev
+}
+
+// CycleError is returned when a graph that has to be acyclic isn't. Cycle starts
+// and ends on the same node
+type CycleError[N comparable] struct {
+	Cycle []N
+}
+
+func (e *CycleError[N]) Error() string {
+	parts := make([]string, len(e.Cycle))
+	for i, n := range e.Cycle {
+		parts[i] = fmt.Sprint(n)
+	}
+	return "cycle detected: " + strings.Join(parts, " -> ")
+}
+
+// CountAllPaths counts the paths from curr to end. memo is keyed by node and is
+// only valid for a single end, pass nil for a fresh one. any cycle reachable
+// from curr without passing through end is an error since the count would be infinite
+func CountAllPaths[N comparable](g Graph[N], curr, end N, memo map[N]int64) (int64, error) {
+	return countPaths(g, curr, end, memo, 1, 0, func(a, b int64) int64 {
+		return a + b
+	})
+}
+
+// CountAllPathsBig is CountAllPaths without overflow. memoized values are never modified
+func CountAllPathsBig[N comparable](g Graph[N], curr, end N, memo map[N]*big.Int) (*big.Int, error) {
+	return countPaths(g, curr, end, memo, big.NewInt(1), new(big.Int), func(a, b *big.Int) *big.Int {
+		return new(big.Int).Add(a, b)
+	})
+}
+
+// CountAllPathsMod is CountAllPaths with the count taken modulo mod, which has to
+// be positive
+func CountAllPathsMod[N comparable](g Graph[N], curr, end N, mod int64, memo map[N]int64) (int64, error) {
+	if mod <= 0 {
+		return 0, fmt.Errorf("modulus must be positive, got %d", mod)
+	}
+	return countPaths(g, curr, end, memo, 1%mod, 0, func(a, b int64) int64 {
+		return (a + b) % mod
+	})
+}
+
+// iterative dfs shared by the CountAllPaths variants so deep graphs can't blow the stack
+func countPaths[N comparable, C any](g Graph[N], start, end N, memo map[N]C, one, zero C, add func(a, b C) C) (C, error) {
+	if start == end {
+		return one, nil
+	}
+	if memo == nil {
+		memo = make(map[N]C)
+	}
+	if count, ok := memo[start]; ok {
+		return count, nil
+	}
+
+	type frame struct {
+		node      N
+		neighbors []N
+		next      int
+		total     C
+	}
+
+	// position of each node on the current dfs stack, for naming cycles
+	onStack := map[N]int{start: 0}
+	stack := []*frame{{node: start, neighbors: g.Neighbors(start), total: zero}}
+
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+
+		// all neighbours done, hand the total to the parent
+		if top.next == len(top.neighbors) {
+			memo[top.node] = top.total
+			delete(onStack, top.node)
+			stack = stack[:len(stack)-1]
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.total = add(parent.total, top.total)
+			}
+			continue
+		}
+
+		neigh := top.neighbors[top.next]
+		top.next++
+
+		if neigh == end {
+			top.total = add(top.total, one)
+			continue
+		}
+		if count, ok := memo[neigh]; ok {
+			top.total = add(top.total, count)
+			continue
+		}
+		if i, ok := onStack[neigh]; ok {
+			cycle := make([]N, 0, len(stack)-i+1)
+			for _, f := range stack[i:] {
+				cycle = append(cycle, f.node)
+			}
+			return zero, &CycleError[N]{Cycle: append(cycle, neigh)}
+		}
+
+		onStack[neigh] = len(stack)
+		stack = append(stack, &frame{node: neigh, neighbors: g.Neighbors(neigh), total: zero})
+	}
+
+	return memo[start], nil
+}
+
+// node plus the set of waypoints seen on the way to it
+type waypointState[N comparable] struct {
+	node N
+	seen uint64
+}
+
+// graph over waypointState so countPaths can memoise on (node, waypoints seen)
+type waypointGraph[N comparable] struct {
+	g    Graph[N]
+	end  N
+	bits map[N]uint64
+}
+
+func (w waypointGraph[N]) Neighbors(s waypointState[N]) []waypointState[N] {
+	// paths stop at end whether or not every waypoint was seen
+	if s.node == w.end {
+		return nil
+	}
+	neighbors := w.g.Neighbors(s.node)
+	out := make([]waypointState[N], len(neighbors))
+	for i, n := range neighbors {
+		out[i] = waypointState[N]{node: n, seen: s.seen | w.bits[n]}
+	}
+	return out
+}
+
+// CountPathsThrough counts the paths from start to end that visit every node in
+// mustVisit, in any order. counts are memoised on (node, waypoints seen) in a single
+// table so each waypoint order shares the work
+func CountPathsThrough[N comparable](g Graph[N], start, end N, mustVisit []N) (int64, error) {
+	if len(mustVisit) > 64 {
+		return 0, fmt.Errorf("too many waypoints: %d, max 64", len(mustVisit))
+	}
+
+	bits := make(map[N]uint64, len(mustVisit))
+	var all uint64
+	for i, n := range mustVisit {
+		bits[n] |= 1 << i
+		all |= 1 << i
+	}
+
+	wg := waypointGraph[N]{g: g, end: end, bits: bits}
+	from := waypointState[N]{node: start, seen: bits[start]}
+	to := waypointState[N]{node: end, seen: all}
+
+	count, err := CountAllPaths(wg, from, to, nil)
+	if err != nil {
+		var cycle *CycleError[waypointState[N]]
+		if errors.As(err, &cycle) {
+			nodes := make([]N, len(cycle.Cycle))
+			for i, s := range cycle.Cycle {
+				nodes[i] = s.node
+			}
+			return 0, &CycleError[N]{Cycle: nodes}
+		}
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,157 @@
+package util
+
+// GridState is a search node made of a grid position plus puzzle specific state
+type GridState[T comparable] struct {
+	Pos   Coordinate
+	State T
+}
+
+// StateMoveFunc returns the nodes reachable in one move from s, with the cost of each move
+type StateMoveFunc[T comparable] func(grid GridInterface, s GridState[T]) []Edge[GridState[T]]
+
+// GridStateSearch runs Dijkstra over (position, state) nodes. the returned path
+// is the full state sequence from start to the goal
+func GridStateSearch[T comparable](grid GridInterface, start GridState[T], goal GoalFunc[GridState[T]], moves StateMoveFunc[T]) SearchResult[GridState[T]] {
+	return SearchDijkstra(start, goal, func(s GridState[T]) []Edge[GridState[T]] {
+		return moves(grid, s)
+	})
+}
+
+// AtPosition is a goal func that ignores state and only checks the position
+func AtPosition[T comparable](goal Coordinate) GoalFunc[GridState[T]] {
+	return func(s GridState[T]) bool { return s.Pos == goal }
+}
+
+// Positions strips the state from a path
+func Positions[T comparable](path []GridState[T]) []Coordinate {
+	coords := make([]Coordinate, len(path))
+	for i, s := range path {
+		coords[i] = s.Pos
+	}
+	return coords
+}
+
+// Heading is the direction currently faced and how many steps have been taken in
+// a straight line. a zero Dir means no facing yet, so any direction is allowed
+type Heading struct {
+	Dir   Coordinate
+	Steps int
+}
+
+// HeadingCostFunc returns the cost of moving between two heading nodes, or -1 if the move is invalid
+type HeadingCostFunc func(grid GridInterface, from, to GridState[Heading]) int
+
+type headingConfig struct {
+	directions   []Coordinate
+	minRun       int
+	maxRun       int // 0 means unlimited
+	allowReverse bool
+	costFunc     HeadingCostFunc
+}
+
+type HeadingOption func(*headingConfig)
+
+// WithRunLimits requires at least minRun steps before turning and at most maxRun steps in a straight line
+func WithRunLimits(minRun, maxRun int) HeadingOption {
+	return func(c *headingConfig) {
+		c.minRun = minRun
+		c.maxRun = maxRun
+	}
+}
+
+// WithTurnPenalty adds penalty to the grid cost whenever the direction changes
+func WithTurnPenalty(penalty int) HeadingOption {
+	return WithHeadingCost(TurnPenaltyCost(penalty))
+}
+
+// WithHeadingCost replaces the default grid cost
+func WithHeadingCost(costFunc HeadingCostFunc) HeadingOption {
+	return func(c *headingConfig) {
+		c.costFunc = costFunc
+	}
+}
+
+// WithReverse allows turning back the way we came
+func WithReverse() HeadingOption {
+	return func(c *headingConfig) {
+		c.allowReverse = true
+	}
+}
+
+// WithHeadingDirections overrides the default Directions4
+func WithHeadingDirections(directions []Coordinate) HeadingOption {
+	return func(c *headingConfig) {
+		c.directions = directions
+	}
+}
+
+// TurnPenaltyCost is the grid cost plus penalty when the move changes direction
+func TurnPenaltyCost(penalty int) HeadingCostFunc {
+	return func(grid GridInterface, from, to GridState[Heading]) int {
+		cost := grid.GetCost(from.Pos, to.Pos)
+		if cost < 0 {
+			return -1
+		}
+		if from.State.Dir != (Coordinate{}) && from.State.Dir != to.State.Dir {
+			cost += penalty
+		}
+		return cost
+	}
+}
+
+// HeadingMoves builds a StateMoveFunc that tracks facing and straight run length
+func HeadingMoves(opts ...HeadingOption) StateMoveFunc[Heading] {
+	cfg := &headingConfig{
+		directions: Directions4,
+		costFunc: func(grid GridInterface, from, to GridState[Heading]) int {
+			return grid.GetCost(from.Pos, to.Pos)
+		},
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(grid GridInterface, s GridState[Heading]) []Edge[GridState[Heading]] {
+		edges := make([]Edge[GridState[Heading]], 0, len(cfg.directions))
+		facing := s.State.Dir != (Coordinate{})
+		for _, dir := range cfg.directions {
+			steps := 1
+			switch {
+			case !facing:
+			case dir == s.State.Dir:
+				if cfg.maxRun > 0 && s.State.Steps >= cfg.maxRun {
+					continue
+				}
+				steps = s.State.Steps + 1
+			case dir == (Coordinate{X: -s.State.Dir.X, Y: -s.State.Dir.Y}):
+				if !cfg.allowReverse || s.State.Steps < cfg.minRun {
+					continue
+				}
+			default:
+				if s.State.Steps < cfg.minRun {
+					continue
+				}
+			}
+
+			next := s.Pos.Add(dir)
+			if !grid.IsValid(next) {
+				continue
+			}
+
+			to := GridState[Heading]{Pos: next, State: Heading{Dir: dir, Steps: steps}}
+			cost := cfg.costFunc(grid, s, to)
+			if cost < 0 {
+				continue
+			}
+			edges = append(edges, Edge[GridState[Heading]]{To: to, Cost: cost})
+		}
+		return edges
+	}
+}
+
+// HeadingGoal matches goal once at least minRun straight steps have been taken
+func HeadingGoal(goal Coordinate, minRun int) GoalFunc[GridState[Heading]] {
+	return func(s GridState[Heading]) bool {
+		return s.Pos == goal && s.State.Steps >= minRun
+	}
+}
@@ -1,96 +1,290 @@
 package util
 
-import "container/heap"
+import (
+	"cmp"
+	"container/heap"
+	"fmt"
+	"iter"
+	"reflect"
+)
 
-type Item[T any] struct {
+type Item[T any, P any] struct {
 	Value    T
-	Priority int
+	Priority P
+	seq      uint64 // insertion order, breaks ties so equal priorities pop FIFO
 }
 
-// internal min heap, used by both MaxHeap and MinHeap
-type minHeap[T any] []Item[T]
+// internal heap ordered by cmp, used by both MaxHeap and MinHeap
+type orderedHeap[T any, P any] struct {
+	items []Item[T, P]
+	cmp   func(a, b P) int
+	seq   uint64
+}
+
+// copies items in and heapifies in O(n)
+func newOrderedHeap[T any, P any](compare func(a, b P) int, items []Item[T, P]) *orderedHeap[T, P] {
+	h := &orderedHeap[T, P]{items: make([]Item[T, P], len(items)), cmp: compare}
+	copy(h.items, items)
+	for i := range h.items {
+		h.items[i].seq = h.seq
+		h.seq++
+	}
+	heap.Init(h)
+	return h
+}
 
-func (pq minHeap[T]) Len() int { return len(pq) }
+func (pq *orderedHeap[T, P]) Len() int { return len(pq.items) }
 
-func (pq minHeap[T]) Less(i, j int) bool {
-	return pq[i].Priority < pq[j].Priority
+func (pq *orderedHeap[T, P]) Less(i, j int) bool {
+	if c := pq.cmp(pq.items[i].Priority, pq.items[j].Priority); c != 0 {
+		return c < 0
+	}
+	return pq.items[i].seq < pq.items[j].seq
 }
 
-func (pq minHeap[T]) Swap(i, j int) {
-	pq[i], pq[j] = pq[j], pq[i]
+func (pq *orderedHeap[T, P]) Swap(i, j int) {
+	pq.items[i], pq.items[j] = pq.items[j], pq.items[i]
 }
 
-func (pq *minHeap[T]) Push(x any) {
-	item := x.(Item[T])
-	*pq = append(*pq, item)
+func (pq *orderedHeap[T, P]) Push(x any) {
+	item := x.(Item[T, P])
+	item.seq = pq.seq
+	pq.seq++
+	pq.items = append(pq.items, item)
 }
 
-func (pq *minHeap[T]) Pop() any {
-	old := *pq
+func (pq *orderedHeap[T, P]) Pop() any {
+	old := pq.items
 	n := len(old)
 	item := old[n-1]
-	*pq = old[0 : n-1]
+	pq.items = old[0 : n-1]
 	return item
 }
 
-// / MaxHeap
-func NewMaxHeap[T any](items ...Item[T]) *MaxHeap[T] {
-	h := make(minHeap[T], 0, len(items))
-	m := &MaxHeap[T]{heap: &h}
-	for _, item := range items {
-		m.Push(item.Value, item.Priority)
-	}
-	return m
+func (pq *orderedHeap[T, P]) clone() *orderedHeap[T, P] {
+	items := make([]Item[T, P], len(pq.items))
+	copy(items, pq.items)
+	return &orderedHeap[T, P]{items: items, cmp: pq.cmp, seq: pq.seq}
 }
 
-type MaxHeap[T any] struct {
-	heap *minHeap[T]
+// heapOrder says which end a priorityQueue pops from. it's a type parameter so
+// a zero value MinHeap or MaxHeap already knows its direction
+type heapOrder interface {
+	descending() bool
 }
 
-func (m *MaxHeap[T]) Init(items ...Item[T]) {
-	h := make(minHeap[T], len(items))
-	heap.Init(&h)
-	m.heap = &h
+type ascending struct{}
+
+func (ascending) descending() bool { return false }
+
+type descending struct{}
+
+func (descending) descending() bool { return true }
+
+// priorityQueue holds everything MaxHeap and MinHeap share. only the order differs
+type priorityQueue[T any, P any, O heapOrder] struct {
+	heap *orderedHeap[T, P]
+}
+
+// get returns the underlying heap, setting a zero value heap up on first use
+// with the natural order of P
+func (q *priorityQueue[T, P, O]) get() *orderedHeap[T, P] {
+	if q.heap == nil {
+		compare := defaultCompare[P]()
+		var order O
+		if order.descending() {
+			compare = reverseCmp(compare)
+		}
+		q.heap = newOrderedHeap[T](compare, nil)
+	}
+	return q.heap
 }
 
-func (m *MaxHeap[T]) Push(thing T, priority int) {
-	heap.Push(m.heap, Item[T]{Priority: -priority, Value: thing})
+// Init replaces the contents with items in O(n), keeping the heap's order
+func (q *priorityQueue[T, P, O]) Init(items ...Item[T, P]) {
+	q.heap = newOrderedHeap(q.get().cmp, items)
 }
 
-func (m *MaxHeap[T]) Pop() T {
-	i := heap.Pop(m.heap).(Item[T])
+func (q *priorityQueue[T, P, O]) Push(thing T, priority P) {
+	heap.Push(q.get(), Item[T, P]{Priority: priority, Value: thing})
+}
+
+func (q *priorityQueue[T, P, O]) Pop() T {
+	i := heap.Pop(q.get()).(Item[T, P])
 	return i.Value
 }
 
-func (m *MaxHeap[T]) PopItem() Item[T] {
-	return heap.Pop(m.heap).(Item[T])
+func (q *priorityQueue[T, P, O]) PopItem() Item[T, P] {
+	return heap.Pop(q.get()).(Item[T, P])
+}
+
+// Peek returns the next value without removing it
+func (q *priorityQueue[T, P, O]) Peek() (T, bool) {
+	item, ok := q.PeekItem()
+	return item.Value, ok
+}
+
+func (q *priorityQueue[T, P, O]) PeekItem() (Item[T, P], bool) {
+	h := q.get()
+	if h.Len() == 0 {
+		return Item[T, P]{}, false
+	}
+	return h.items[0], true
 }
 
-func (m *MaxHeap[T]) Len() int { return len(*m.heap) }
+func (q *priorityQueue[T, P, O]) Len() int { return q.get().Len() }
+
+// All iterates over the heap without popping, in no particular order
+func (q *priorityQueue[T, P, O]) All() iter.Seq2[T, P] {
+	return func(yield func(T, P) bool) {
+		for _, item := range q.get().items {
+			if !yield(item.Value, item.Priority) {
+				return
+			}
+		}
+	}
+}
+
+// Sorted iterates in priority order without modifying the heap
+func (q *priorityQueue[T, P, O]) Sorted() iter.Seq2[T, P] {
+	return drain(q.get().clone())
+}
+
+// Drain pops items in priority order as they're iterated. stopping early leaves
+// the rest in the heap
+func (q *priorityQueue[T, P, O]) Drain() iter.Seq2[T, P] {
+	return drain(q.get())
+}
+
+func drain[T any, P any](h *orderedHeap[T, P]) iter.Seq2[T, P] {
+	return func(yield func(T, P) bool) {
+		for h.Len() > 0 {
+			item := heap.Pop(h).(Item[T, P])
+			if !yield(item.Value, item.Priority) {
+				return
+			}
+		}
+	}
+}
+
+// defaultCompare is cmp.Compare for P, found at runtime because zero value heaps
+// only know P as any. named types like `type Dist int` go through kindCompare.
+// priorities of other types need the Func constructors
+func defaultCompare[P any]() func(a, b P) int {
+	var zero P
+	var compare any
+	switch any(zero).(type) {
+	case int:
+		compare = cmp.Compare[int]
+	case int8:
+		compare = cmp.Compare[int8]
+	case int16:
+		compare = cmp.Compare[int16]
+	case int32:
+		compare = cmp.Compare[int32]
+	case int64:
+		compare = cmp.Compare[int64]
+	case uint:
+		compare = cmp.Compare[uint]
+	case uint8:
+		compare = cmp.Compare[uint8]
+	case uint16:
+		compare = cmp.Compare[uint16]
+	case uint32:
+		compare = cmp.Compare[uint32]
+	case uint64:
+		compare = cmp.Compare[uint64]
+	case float32:
+		compare = cmp.Compare[float32]
+	case float64:
+		compare = cmp.Compare[float64]
+	case string:
+		compare = cmp.Compare[string]
+	default:
+		return kindCompare[P]()
+	}
+	return compare.(func(a, b P) int)
+}
+
+// kindCompare orders P by its underlying kind. it goes through reflect on every
+// call, so it's slower than the built in cases
+func kindCompare[P any]() func(a, b P) int {
+	t := reflect.TypeFor[P]()
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(a, b P) int { return cmp.Compare(reflect.ValueOf(a).Int(), reflect.ValueOf(b).Int()) }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(a, b P) int { return cmp.Compare(reflect.ValueOf(a).Uint(), reflect.ValueOf(b).Uint()) }
+	case reflect.Float32, reflect.Float64:
+		return func(a, b P) int { return cmp.Compare(reflect.ValueOf(a).Float(), reflect.ValueOf(b).Float()) }
+	case reflect.String:
+		return func(a, b P) int { return cmp.Compare(reflect.ValueOf(a).String(), reflect.ValueOf(b).String()) }
+	}
+	panic(fmt.Sprintf("heap: no natural order for %v priorities, use NewMinHeapFunc or NewMaxHeapFunc", t))
+}
+
+func reverseCmp[P any](compare func(a, b P) int) func(a, b P) int {
+	return func(a, b P) int { return compare(b, a) }
+}
+
+// / MaxHeap
+func NewMaxHeap[T any, P cmp.Ordered](items ...Item[T, P]) *MaxHeap[T, P] {
+	return NewMaxHeapFunc(cmp.Compare[P], items...)
+}
+
+// NewMaxHeapFunc orders priorities with compare, for priorities that aren't
+// cmp.Ordered such as tuples
+func NewMaxHeapFunc[T any, P any](compare func(a, b P) int, items ...Item[T, P]) *MaxHeap[T, P] {
+	return &MaxHeap[T, P]{priorityQueue[T, P, descending]{heap: newOrderedHeap(reverseCmp(compare), items)}}
+}
+
+// NewMaxHeapOf builds a heap where each value is its own priority
+func NewMaxHeapOf[T cmp.Ordered](values ...T) *MaxHeap[T, T] {
+	return NewMaxHeap(selfItems(values)...)
+}
+
+// MaxHeap pops the highest priority first. the zero value is an empty heap using
+// the natural order of P
+type MaxHeap[T any, P any] struct {
+	priorityQueue[T, P, descending]
+}
+
+// Clone returns an independent copy of the heap
+func (m *MaxHeap[T, P]) Clone() *MaxHeap[T, P] {
+	return &MaxHeap[T, P]{priorityQueue[T, P, descending]{heap: m.get().clone()}}
+}
 
 // MinHeap
-func NewMinHeap[T any](items ...Item[T]) *MinHeap[T] {
-	h := make(minHeap[T], len(items))
-	copy(h, items)
-	heap.Init(&h)
-	return &MinHeap[T]{heap: &h}
+func NewMinHeap[T any, P cmp.Ordered](items ...Item[T, P]) *MinHeap[T, P] {
+	return NewMinHeapFunc(cmp.Compare[P], items...)
 }
 
-type MinHeap[T any] struct {
-	heap *minHeap[T]
+// NewMinHeapFunc orders priorities with compare, for priorities that aren't
+// cmp.Ordered such as tuples
+func NewMinHeapFunc[T any, P any](compare func(a, b P) int, items ...Item[T, P]) *MinHeap[T, P] {
+	return &MinHeap[T, P]{priorityQueue[T, P, ascending]{heap: newOrderedHeap(compare, items)}}
 }
 
-func (m *MinHeap[T]) Push(thing T, priority int) {
-	heap.Push(m.heap, Item[T]{Priority: priority, Value: thing})
+// NewMinHeapOf builds a heap where each value is its own priority
+func NewMinHeapOf[T cmp.Ordered](values ...T) *MinHeap[T, T] {
+	return NewMinHeap(selfItems(values)...)
 }
 
-func (m *MinHeap[T]) Pop() T {
-	i := heap.Pop(m.heap).(Item[T])
-	return i.Value
+// MinHeap pops the lowest priority first. the zero value is an empty heap using
+// the natural order of P
+type MinHeap[T any, P any] struct {
+	priorityQueue[T, P, ascending]
 }
 
-func (m *MinHeap[T]) PopItem() Item[T] {
-	return heap.Pop(m.heap).(Item[T])
+// Clone returns an independent copy of the heap
+func (m *MinHeap[T, P]) Clone() *MinHeap[T, P] {
+	return &MinHeap[T, P]{priorityQueue[T, P, ascending]{heap: m.get().clone()}}
 }
 
-func (m *MinHeap[T]) Len() int { return len(*m.heap) }
+func selfItems[T any](values []T) []Item[T, T] {
+	items := make([]Item[T, T], len(values))
+	for i, v := range values {
+		items[i] = Item[T, T]{Value: v, Priority: v}
+	}
+	return items
+}
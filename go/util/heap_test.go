@@ -0,0 +1,273 @@
+package util
+
+import (
+	"iter"
+	"slices"
+	"testing"
+)
+
+func collect[T, P any](seq iter.Seq2[T, P]) []T {
+	out := []T{}
+	for v := range seq {
+		out = append(out, v)
+	}
+	return out
+}
+
+func popAll[T any](h interface {
+	Len() int
+	Pop() T
+}) []T {
+	out := []T{}
+	for h.Len() > 0 {
+		out = append(out, h.Pop())
+	}
+	return out
+}
+
+func letterItems() []Item[string, int] {
+	return []Item[string, int]{
+		{Value: "c", Priority: 3},
+		{Value: "a", Priority: 1},
+		{Value: "e", Priority: 5},
+		{Value: "b", Priority: 2},
+		{Value: "d", Priority: 4},
+	}
+}
+
+func TestHeapOrder(t *testing.T) {
+	if got := popAll[string](NewMaxHeap(letterItems()...)); !slices.Equal(got, []string{"e", "d", "c", "b", "a"}) {
+		t.Errorf("max heap popped %v", got)
+	}
+	if got := popAll[string](NewMinHeap(letterItems()...)); !slices.Equal(got, []string{"a", "b", "c", "d", "e"}) {
+		t.Errorf("min heap popped %v", got)
+	}
+
+	// pushing one at a time has to agree with bulk construction
+	maxHeap := NewMaxHeap[string, int]()
+	minHeap := NewMinHeap[string, int]()
+	for _, item := range letterItems() {
+		maxHeap.Push(item.Value, item.Priority)
+		minHeap.Push(item.Value, item.Priority)
+	}
+	if got := popAll[string](maxHeap); !slices.Equal(got, []string{"e", "d", "c", "b", "a"}) {
+		t.Errorf("max heap after pushes popped %v", got)
+	}
+	if got := popAll[string](minHeap); !slices.Equal(got, []string{"a", "b", "c", "d", "e"}) {
+		t.Errorf("min heap after pushes popped %v", got)
+	}
+}
+
+func TestHeapEqualPrioritiesAreFIFO(t *testing.T) {
+	items := []Item[string, int]{
+		{Value: "first", Priority: 1},
+		{Value: "second", Priority: 1},
+		{Value: "high", Priority: 2},
+		{Value: "third", Priority: 1},
+	}
+	want := []string{"first", "second", "third"}
+
+	minHeap := NewMinHeap(items...)
+	if got := popAll[string](minHeap)[:3]; !slices.Equal(got, want) {
+		t.Errorf("min heap ties popped %v, want %v", got, want)
+	}
+
+	maxHeap := NewMaxHeap(items...)
+	maxHeap.Pop() // high
+	if got := popAll[string](maxHeap); !slices.Equal(got, want) {
+		t.Errorf("max heap ties popped %v, want %v", got, want)
+	}
+
+	pushed := NewMinHeap[string, int]()
+	for _, v := range want {
+		pushed.Push(v, 7)
+	}
+	if got := popAll[string](pushed); !slices.Equal(got, want) {
+		t.Errorf("pushed ties popped %v, want %v", got, want)
+	}
+}
+
+func TestHeapInit(t *testing.T) {
+	t.Run("zero value max", func(t *testing.T) {
+		var h MaxHeap[string, int]
+		h.Init(letterItems()...)
+		if got := popAll[string](&h); !slices.Equal(got, []string{"e", "d", "c", "b", "a"}) {
+			t.Errorf("popped %v", got)
+		}
+	})
+
+	t.Run("zero value min", func(t *testing.T) {
+		var h MinHeap[string, int]
+		h.Init(letterItems()...)
+		if got := popAll[string](&h); !slices.Equal(got, []string{"a", "b", "c", "d", "e"}) {
+			t.Errorf("popped %v", got)
+		}
+	})
+
+	t.Run("zero value without init", func(t *testing.T) {
+		var h MaxHeap[string, int]
+		if h.Len() != 0 {
+			t.Errorf("Len = %d, want 0", h.Len())
+		}
+		if _, ok := h.Peek(); ok {
+			t.Error("Peek on an empty heap reported an item")
+		}
+		h.Push("low", 1)
+		h.Push("high", 9)
+		if got := h.Pop(); got != "high" {
+			t.Errorf("Pop = %q, want high", got)
+		}
+	})
+
+	t.Run("used heap", func(t *testing.T) {
+		h := NewMaxHeap[string, int]()
+		h.Push("old", 100)
+		h.Push("older", 200)
+		h.Pop()
+
+		h.Init(letterItems()...)
+		if h.Len() != 5 {
+			t.Fatalf("Len = %d, want 5", h.Len())
+		}
+		if got := popAll[string](h); !slices.Equal(got, []string{"e", "d", "c", "b", "a"}) {
+			t.Errorf("popped %v, Init should replace the contents and keep max order", got)
+		}
+	})
+
+	t.Run("custom order survives", func(t *testing.T) {
+		byLength := func(a, b string) int { return len(a) - len(b) }
+		h := NewMinHeapFunc[int](byLength)
+		h.Init(Item[int, string]{Value: 3, Priority: "ccc"}, Item[int, string]{Value: 1, Priority: "a"})
+		if got := popAll[int](h); !slices.Equal(got, []int{1, 3}) {
+			t.Errorf("popped %v", got)
+		}
+	})
+}
+
+type dist int
+
+type label string
+
+func TestHeapZeroValueNamedPriority(t *testing.T) {
+	var minHeap MinHeap[string, dist]
+	minHeap.Push("far", 30)
+	minHeap.Push("near", 1)
+	minHeap.Push("mid", 12)
+	if got := popAll[string](&minHeap); !slices.Equal(got, []string{"near", "mid", "far"}) {
+		t.Errorf("min heap popped %v", got)
+	}
+
+	var maxHeap MaxHeap[int, label]
+	maxHeap.Init(Item[int, label]{Value: 1, Priority: "apple"}, Item[int, label]{Value: 2, Priority: "pear"})
+	if got := popAll[int](&maxHeap); !slices.Equal(got, []int{2, 1}) {
+		t.Errorf("max heap popped %v", got)
+	}
+}
+
+func TestHeapPeek(t *testing.T) {
+	h := NewMaxHeap(letterItems()...)
+	v, ok := h.Peek()
+	if !ok || v != "e" {
+		t.Errorf("Peek = %q, %v, want e, true", v, ok)
+	}
+	item, ok := h.PeekItem()
+	if !ok || item.Value != "e" || item.Priority != 5 {
+		t.Errorf("PeekItem = %+v, %v", item, ok)
+	}
+	if h.Len() != 5 {
+		t.Errorf("Peek changed Len to %d", h.Len())
+	}
+
+	empty := NewMinHeap[string, int]()
+	if _, ok := empty.PeekItem(); ok {
+		t.Error("PeekItem on an empty heap reported an item")
+	}
+}
+
+func TestHeapSortedLeavesHeapAlone(t *testing.T) {
+	h := NewMinHeap(letterItems()...)
+	if got := collect(h.Sorted()); !slices.Equal(got, []string{"a", "b", "c", "d", "e"}) {
+		t.Errorf("Sorted gave %v", got)
+	}
+	if h.Len() != 5 {
+		t.Errorf("Len after Sorted = %d, want 5", h.Len())
+	}
+	if got := popAll[string](h); !slices.Equal(got, []string{"a", "b", "c", "d", "e"}) {
+		t.Errorf("popped %v after Sorted", got)
+	}
+}
+
+func TestHeapDrain(t *testing.T) {
+	h := NewMaxHeap(letterItems()...)
+	got := []string{}
+	for v := range h.Drain() {
+		got = append(got, v)
+		if len(got) == 2 {
+			break
+		}
+	}
+	if !slices.Equal(got, []string{"e", "d"}) {
+		t.Errorf("Drain gave %v", got)
+	}
+	if h.Len() != 3 {
+		t.Fatalf("Len after stopping Drain = %d, want 3", h.Len())
+	}
+	if rest := collect(h.Drain()); !slices.Equal(rest, []string{"c", "b", "a"}) {
+		t.Errorf("rest of Drain gave %v", rest)
+	}
+	if h.Len() != 0 {
+		t.Errorf("Len after full Drain = %d, want 0", h.Len())
+	}
+}
+
+func TestHeapClone(t *testing.T) {
+	orig := NewMinHeap(letterItems()...)
+	clone := orig.Clone()
+
+	clone.Pop()
+	clone.Push("z", 0)
+	if orig.Len() != 5 {
+		t.Errorf("original Len = %d after changing the clone", orig.Len())
+	}
+	if got := popAll[string](orig); !slices.Equal(got, []string{"a", "b", "c", "d", "e"}) {
+		t.Errorf("original popped %v", got)
+	}
+	if got := popAll[string](clone); !slices.Equal(got, []string{"z", "b", "c", "d", "e"}) {
+		t.Errorf("clone popped %v", got)
+	}
+
+	maxOrig := NewMaxHeap(letterItems()...)
+	maxClone := maxOrig.Clone()
+	maxOrig.Pop()
+	if got := popAll[string](maxClone); !slices.Equal(got, []string{"e", "d", "c", "b", "a"}) {
+		t.Errorf("max clone popped %v", got)
+	}
+}
+
+func TestHeapAll(t *testing.T) {
+	for name, h := range map[string]interface {
+		All() iter.Seq2[string, int]
+		Len() int
+	}{
+		"max": NewMaxHeap(letterItems()...),
+		"min": NewMinHeap(letterItems()...),
+	} {
+		got := collect(h.All())
+		slices.Sort(got)
+		if !slices.Equal(got, []string{"a", "b", "c", "d", "e"}) {
+			t.Errorf("%s All gave %v", name, got)
+		}
+		if h.Len() != 5 {
+			t.Errorf("%s Len after All = %d, want 5", name, h.Len())
+		}
+	}
+}
+
+func TestHeapOf(t *testing.T) {
+	if got := popAll[int](NewMaxHeapOf(3, 1, 4, 1, 5)); !slices.Equal(got, []int{5, 4, 3, 1, 1}) {
+		t.Errorf("NewMaxHeapOf popped %v", got)
+	}
+	if got := popAll[int](NewMinHeapOf(3, 1, 4, 1, 5)); !slices.Equal(got, []int{1, 1, 3, 4, 5}) {
+		t.Errorf("NewMinHeapOf popped %v", got)
+	}
+}
@@ -0,0 +1,133 @@
+package util
+
+import (
+	"cmp"
+	"container/heap"
+)
+
+type indexedItem[K comparable, P any] struct {
+	key      K
+	priority P
+	seq      uint64
+}
+
+// heap.Interface for IndexedMinHeap, keeping pos in sync as items move
+type indexedHeap[K comparable, P any] struct {
+	items []indexedItem[K, P]
+	pos   map[K]int
+	cmp   func(a, b P) int
+}
+
+func (h *indexedHeap[K, P]) Len() int { return len(h.items) }
+
+func (h *indexedHeap[K, P]) Less(i, j int) bool {
+	if c := h.cmp(h.items[i].priority, h.items[j].priority); c != 0 {
+		return c < 0
+	}
+	return h.items[i].seq < h.items[j].seq
+}
+
+func (h *indexedHeap[K, P]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.pos[h.items[i].key] = i
+	h.pos[h.items[j].key] = j
+}
+
+func (h *indexedHeap[K, P]) Push(x any) {
+	item := x.(indexedItem[K, P])
+	h.pos[item.key] = len(h.items)
+	h.items = append(h.items, item)
+}
+
+func (h *indexedHeap[K, P]) Pop() any {
+	n := len(h.items)
+	item := h.items[n-1]
+	h.items = h.items[:n-1]
+	delete(h.pos, item.key)
+	return item
+}
+
+// IndexedMinHeap is a min heap holding each key at most once, so a key's
+// priority can be changed in place instead of pushing a duplicate. equal
+// priorities pop in insertion order
+type IndexedMinHeap[K comparable, P any] struct {
+	heap *indexedHeap[K, P]
+	seq  uint64
+}
+
+func NewIndexedMinHeap[K comparable, P cmp.Ordered]() *IndexedMinHeap[K, P] {
+	return NewIndexedMinHeapFunc[K](cmp.Compare[P])
+}
+
+// NewIndexedMinHeapFunc orders priorities with compare, for priorities that
+// aren't cmp.Ordered such as tuples
+func NewIndexedMinHeapFunc[K comparable, P any](compare func(a, b P) int) *IndexedMinHeap[K, P] {
+	return &IndexedMinHeap[K, P]{heap: &indexedHeap[K, P]{pos: make(map[K]int), cmp: compare}}
+}
+
+func (h *IndexedMinHeap[K, P]) Len() int { return h.heap.Len() }
+
+func (h *IndexedMinHeap[K, P]) Contains(key K) bool {
+	_, ok := h.heap.pos[key]
+	return ok
+}
+
+// Priority returns the current priority of key
+func (h *IndexedMinHeap[K, P]) Priority(key K) (P, bool) {
+	i, ok := h.heap.pos[key]
+	if !ok {
+		var zero P
+		return zero, false
+	}
+	return h.heap.items[i].priority, true
+}
+
+// Upsert adds key or sets its priority, whether that raises or lowers it
+func (h *IndexedMinHeap[K, P]) Upsert(key K, priority P) {
+	if i, ok := h.heap.pos[key]; ok {
+		h.heap.items[i].priority = priority
+		heap.Fix(h.heap, i)
+		return
+	}
+	heap.Push(h.heap, indexedItem[K, P]{key: key, priority: priority, seq: h.seq})
+	h.seq++
+}
+
+// DecreaseKey lowers the priority of key. returns false and does nothing if key
+// isn't in the heap or priority isn't lower
+func (h *IndexedMinHeap[K, P]) DecreaseKey(key K, priority P) bool {
+	i, ok := h.heap.pos[key]
+	if !ok || h.heap.cmp(priority, h.heap.items[i].priority) >= 0 {
+		return false
+	}
+	h.heap.items[i].priority = priority
+	heap.Fix(h.heap, i)
+	return true
+}
+
+// Remove takes key out of the heap. returns false if it wasn't there
+func (h *IndexedMinHeap[K, P]) Remove(key K) bool {
+	i, ok := h.heap.pos[key]
+	if !ok {
+		return false
+	}
+	heap.Remove(h.heap, i)
+	return true
+}
+
+// PeekMin returns the lowest priority key without removing it
+func (h *IndexedMinHeap[K, P]) PeekMin() (K, P, bool) {
+	if h.heap.Len() == 0 {
+		var key K
+		var priority P
+		return key, priority, false
+	}
+	item := h.heap.items[0]
+	return item.key, item.priority, true
+}
+
+// PopMin removes and returns the lowest priority key. panics if the heap is empty
+func (h *IndexedMinHeap[K, P]) PopMin() (K, P) {
+	item := heap.Pop(h.heap).(indexedItem[K, P])
+	return item.key, item.priority
+}
@@ -0,0 +1,158 @@
+package util
+
+import (
+	"fmt"
+	"iter"
+	"math"
+	"math/big"
+	"slices"
+	"sort"
+)
+
+// Interval is the half open span of integers [Start, End). use Inclusive to
+// build one from puzzle style lo-hi ranges
+type Interval struct {
+	Start, End int
+}
+
+// Inclusive is the interval covering lo through hi. End is hi+1, so hi can't be
+// math.MaxInt. that would wrap round to an empty interval, so it panics instead
+func Inclusive(lo, hi int) Interval {
+	if hi == math.MaxInt {
+		panic(fmt.Sprintf("inclusive range %d-%d ends at math.MaxInt, which an Interval can't hold", lo, hi))
+	}
+	return Interval{Start: lo, End: hi + 1}
+}
+
+// Last is the final integer in the interval, for going back to inclusive ranges
+func (iv Interval) Last() int { return iv.End - 1 }
+
+func (iv Interval) Len() int { return max(iv.End-iv.Start, 0) }
+
+func (iv Interval) Empty() bool { return iv.End <= iv.Start }
+
+func (iv Interval) Contains(x int) bool { return x >= iv.Start && x < iv.End }
+
+// IntervalSet is a set of integers stored as sorted, disjoint, non touching spans
+type IntervalSet struct {
+	spans []Interval
+}
+
+func NewIntervalSet(intervals ...Interval) *IntervalSet {
+	s := &IntervalSet{}
+	for _, iv := range intervals {
+		s.Add(iv)
+	}
+	return s
+}
+
+func (s *IntervalSet) Clone() *IntervalSet {
+	return &IntervalSet{spans: slices.Clone(s.spans)}
+}
+
+// Add merges iv into the set, joining any spans it overlaps or touches
+func (s *IntervalSet) Add(iv Interval) {
+	if iv.Empty() {
+		return
+	}
+	// spans[i:j] overlap or touch iv
+	i := sort.Search(len(s.spans), func(k int) bool { return s.spans[k].End >= iv.Start })
+	j := sort.Search(len(s.spans), func(k int) bool { return s.spans[k].Start > iv.End })
+	if i < j {
+		iv.Start = min(iv.Start, s.spans[i].Start)
+		iv.End = max(iv.End, s.spans[j-1].End)
+	}
+	s.spans = slices.Replace(s.spans, i, j, iv)
+}
+
+// Remove takes iv out of the set, splitting a span if iv is in the middle of it
+func (s *IntervalSet) Remove(iv Interval) {
+	if iv.Empty() {
+		return
+	}
+	// spans[i:j] overlap iv
+	i := sort.Search(len(s.spans), func(k int) bool { return s.spans[k].End > iv.Start })
+	j := sort.Search(len(s.spans), func(k int) bool { return s.spans[k].Start >= iv.End })
+	if i >= j {
+		return
+	}
+
+	leftovers := make([]Interval, 0, 2)
+	if left := (Interval{Start: s.spans[i].Start, End: iv.Start}); !left.Empty() {
+		leftovers = append(leftovers, left)
+	}
+	if right := (Interval{Start: iv.End, End: s.spans[j-1].End}); !right.Empty() {
+		leftovers = append(leftovers, right)
+	}
+	s.spans = slices.Replace(s.spans, i, j, leftovers...)
+}
+
+// Contains reports whether x is in the set in O(log n)
+func (s *IntervalSet) Contains(x int) bool {
+	i := sort.Search(len(s.spans), func(k int) bool { return s.spans[k].End > x })
+	return i < len(s.spans) && s.spans[i].Contains(x)
+}
+
+// Union returns a new set with everything in either set
+func (s *IntervalSet) Union(other *IntervalSet) *IntervalSet {
+	out := s.Clone()
+	for _, iv := range other.spans {
+		out.Add(iv)
+	}
+	return out
+}
+
+// Intersect returns a new set with everything in both sets
+func (s *IntervalSet) Intersect(other *IntervalSet) *IntervalSet {
+	out := &IntervalSet{}
+	a, b := s.spans, other.spans
+	for len(a) > 0 && len(b) > 0 {
+		overlap := Interval{Start: max(a[0].Start, b[0].Start), End: min(a[0].End, b[0].End)}
+		if !overlap.Empty() {
+			out.spans = append(out.spans, overlap)
+		}
+		// drop whichever finishes first, it can't overlap anything further on
+		if a[0].End < b[0].End {
+			a = a[1:]
+		} else {
+			b = b[1:]
+		}
+	}
+	return out
+}
+
+// Subtract returns a new set with everything in s that isn't in other
+func (s *IntervalSet) Subtract(other *IntervalSet) *IntervalSet {
+	out := s.Clone()
+	for _, iv := range other.spans {
+		out.Remove(iv)
+	}
+	return out
+}
+
+// Len is the number of integers covered. see LenBig if that can overflow
+func (s *IntervalSet) Len() int {
+	total := 0
+	for _, iv := range s.spans {
+		total += iv.Len()
+	}
+	return total
+}
+
+// LenBig is Len without overflow, even for spans across most of the int range
+func (s *IntervalSet) LenBig() *big.Int {
+	total := new(big.Int)
+	for _, iv := range s.spans {
+		size := new(big.Int).Sub(big.NewInt(int64(iv.End)), big.NewInt(int64(iv.Start)))
+		total.Add(total, size)
+	}
+	return total
+}
+
+// NumSpans is the number of separate spans after merging
+func (s *IntervalSet) NumSpans() int { return len(s.spans) }
+
+// Spans iterates over the merged spans in order
+func (s *IntervalSet) Spans() iter.Seq[Interval] {
+	return slices.Values(s.spans)
+}
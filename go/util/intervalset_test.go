@@ -0,0 +1,42 @@
+package util
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"testing"
+)
+
+func TestInclusive(t *testing.T) {
+	iv := Inclusive(3, 5)
+	if iv != (Interval{Start: 3, End: 6}) || iv.Last() != 5 || iv.Len() != 3 {
+		t.Errorf("Inclusive(3, 5) = %+v", iv)
+	}
+
+	top := Inclusive(math.MaxInt-2, math.MaxInt-1)
+	if top.Empty() || top.Len() != 2 || top.Last() != math.MaxInt-1 {
+		t.Errorf("Inclusive just under math.MaxInt = %+v", top)
+	}
+}
+
+func TestInclusiveMaxIntPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Inclusive(0, math.MaxInt) didn't panic")
+		}
+	}()
+	Inclusive(0, math.MaxInt)
+}
+
+func TestIntervalSetLenBig(t *testing.T) {
+	s := NewIntervalSet(Inclusive(math.MinInt, -1), Inclusive(0, math.MaxInt-1))
+	if s.NumSpans() != 1 {
+		t.Errorf("NumSpans = %d, want 1", s.NumSpans())
+	}
+	// every int but math.MaxInt
+	want := new(big.Int).Lsh(big.NewInt(1), strconv.IntSize)
+	want.Sub(want, big.NewInt(1))
+	if got := s.LenBig(); got.Cmp(want) != 0 {
+		t.Errorf("LenBig = %v, want %v", got, want)
+	}
+}
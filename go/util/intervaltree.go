@@ -0,0 +1,130 @@
+package util
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"sort"
+)
+
+// IntervalEntry is one of the original intervals in an IntervalTree with the
+// value it was stored with
+type IntervalEntry[V any] struct {
+	Interval Interval
+	Value    V
+}
+
+// IntervalTree keeps every interval separately, unlike IntervalSet, so it can say
+// which of them cover a point. the tree is implicit over the entries sorted by
+// start, each node knowing the furthest end below it. it's rebuilt on the first
+// query after an Insert
+type IntervalTree[V any] struct {
+	entries []IntervalEntry[V]
+	maxEnd  []int // furthest end in the subtree rooted at each entry
+	starts  []int // sorted starts of the non empty intervals, for counting
+	ends    []int // sorted ends of the same
+	dirty   bool
+}
+
+func NewIntervalTree[V any](entries ...IntervalEntry[V]) *IntervalTree[V] {
+	return &IntervalTree[V]{entries: slices.Clone(entries), dirty: true}
+}
+
+func (t *IntervalTree[V]) Insert(iv Interval, value V) {
+	t.entries = append(t.entries, IntervalEntry[V]{Interval: iv, Value: value})
+	t.dirty = true
+}
+
+func (t *IntervalTree[V]) Len() int { return len(t.entries) }
+
+func (t *IntervalTree[V]) build() {
+	if !t.dirty {
+		return
+	}
+	t.dirty = false
+
+	slices.SortStableFunc(t.entries, func(a, b IntervalEntry[V]) int {
+		if c := cmp.Compare(a.Interval.Start, b.Interval.Start); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Interval.End, b.Interval.End)
+	})
+
+	n := len(t.entries)
+	t.maxEnd = make([]int, n)
+	t.starts = make([]int, 0, n)
+	t.ends = make([]int, 0, n)
+	for _, e := range t.entries {
+		if !e.Interval.Empty() {
+			t.starts = append(t.starts, e.Interval.Start)
+			t.ends = append(t.ends, e.Interval.End)
+		}
+	}
+	slices.Sort(t.ends)
+
+	var fill func(lo, hi int) int
+	fill = func(lo, hi int) int {
+		if lo >= hi {
+			return math.MinInt
+		}
+		mid := (lo + hi) / 2
+		t.maxEnd[mid] = max(t.entries[mid].Interval.End, fill(lo, mid), fill(mid+1, hi))
+		return t.maxEnd[mid]
+	}
+	fill(0, n)
+}
+
+// Stab returns every entry whose interval contains x, ordered by start
+func (t *IntervalTree[V]) Stab(x int) []IntervalEntry[V] {
+	return t.Overlapping(Interval{Start: x, End: x + 1})
+}
+
+// Overlapping returns every entry whose interval shares at least one integer with
+// iv, ordered by start
+func (t *IntervalTree[V]) Overlapping(iv Interval) []IntervalEntry[V] {
+	t.build()
+	found := []IntervalEntry[V]{}
+	if iv.Empty() {
+		return found
+	}
+
+	var search func(lo, hi int)
+	search = func(lo, hi int) {
+		if lo >= hi {
+			return
+		}
+		mid := (lo + hi) / 2
+		// nothing down here reaches iv
+		if t.maxEnd[mid] <= iv.Start {
+			return
+		}
+		search(lo, mid)
+		e := t.entries[mid]
+		if e.Interval.Start < iv.End && iv.Start < e.Interval.End && !e.Interval.Empty() {
+			found = append(found, e)
+		}
+		// everything to the right starts at or after mid
+		if e.Interval.Start < iv.End {
+			search(mid+1, hi)
+		}
+	}
+	search(0, len(t.entries))
+	return found
+}
+
+// CountStab is len(Stab(x)) in O(log n)
+func (t *IntervalTree[V]) CountStab(x int) int {
+	return t.CountOverlapping(Interval{Start: x, End: x + 1})
+}
+
+// CountOverlapping is len(Overlapping(iv)) in O(log n). it counts every
+// interval that neither ends before iv starts nor starts after it ends
+func (t *IntervalTree[V]) CountOverlapping(iv Interval) int {
+	t.build()
+	if iv.Empty() {
+		return 0
+	}
+	startsAfter := len(t.starts) - sort.SearchInts(t.starts, iv.End)
+	endsBefore := sort.SearchInts(t.ends, iv.Start+1)
+	return len(t.starts) - startsAfter - endsBefore
+}
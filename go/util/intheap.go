@@ -1,33 +0,0 @@
-package util
-
-type IntHeap []int
-
-// Len is the number of elements in the collection.
-func (h IntHeap) Len() int {
-	return len(h)
-}
-
-// Less reports whether the element with index i
-// must sort before the element with index j.
-// This will determine whether the heap is a min heap or a max heap
-func (h IntHeap) Less(i int, j int) bool {
-	return h[i] < h[j]
-}
-
-// Swap swaps the elements with indexes i and j.
-func (h IntHeap) Swap(i int, j int) {
-	h[i], h[j] = h[j], h[i]
-}
-
-// Push and Pop are used to append and remove the last element of the slice
-func (h *IntHeap) Push(x any) {
-	*h = append(*h, x.(int))
-}
-
-func (h *IntHeap) Pop() any {
-	old := *h
-	n := len(old)
-	x := old[n-1]
-	*h = old[0 : n-1]
-	return x
-}
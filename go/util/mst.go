@@ -0,0 +1,295 @@
+package util
+
+import (
+	"cmp"
+	"container/heap"
+	"slices"
+	"sort"
+)
+
+type MSTEdge[N comparable, W Number] struct {
+	From, To N
+	Weight   W
+	// Rank is the position of the edge among all the candidate edges in weight
+	// order, ties in the order the candidates are listed. Prim works it out
+	// afterwards so the ranks agree with Kruskal
+	Rank int
+}
+
+// MST is a minimum spanning tree, or forest if the graph isn't connected
+type MST[N comparable, W Number] struct {
+	Edges     []MSTEdge[N, W] // in the order they were added
+	Total     W
+	Connected bool // whether the tree spans every node
+	nodes     []N
+}
+
+// Last returns the edge that finally connected the graph
+func (m *MST[N, W]) Last() (MSTEdge[N, W], bool) {
+	if !m.Connected || len(m.Edges) == 0 {
+		return MSTEdge[N, W]{}, false
+	}
+	return m.Edges[len(m.Edges)-1], true
+}
+
+// ClusterAfterK returns the component sizes, largest first, after the k cheapest
+// candidate edges have been considered. this is single-linkage clustering. with
+// tied weights Kruskal and Prim can pick different trees, so they only agree for
+// certain when k doesn't split a run of equal weights
+func (m *MST[N, W]) ClusterAfterK(k int) []int {
+	ds := NewDisjointSet(m.nodes...)
+	for _, e := range m.Edges {
+		if e.Rank < k {
+			ds.Union(e.From, e.To)
+		}
+	}
+
+	sizes := make([]int, 0, ds.NumComponents())
+	for _, comp := range ds.Components() {
+		sizes = append(sizes, len(comp))
+	}
+	slices.SortFunc(sizes, func(a, b int) int { return b - a })
+	return sizes
+}
+
+// kruskal over candidates that are already in weight order
+func kruskal[N comparable, W Number](nodes []N, candidates []MSTEdge[N, W]) *MST[N, W] {
+	m := &MST[N, W]{nodes: nodes, Connected: len(nodes) <= 1}
+	ds := NewDisjointSet(nodes...)
+	for i, e := range candidates {
+		if ds.NumComponents() == 1 {
+			break
+		}
+		if !ds.Union(e.From, e.To) {
+			continue
+		}
+		e.Rank = i
+		m.Edges = append(m.Edges, e)
+		m.Total += e.Weight
+	}
+	m.Connected = ds.NumComponents() <= 1
+	return m
+}
+
+// graphCandidates lists every edge of g once, in the order Kruskal breaks ties
+func graphCandidates[N comparable, W Number](g *WeightedGraph[N, W]) func(yield func(N, N, W) bool) {
+	return func(yield func(N, N, W) bool) {
+		index := make(map[N]int, len(g.nodes))
+		for i, n := range g.nodes {
+			index[n] = i
+		}
+		for _, from := range g.nodes {
+			for _, e := range g.adj[from] {
+				// undirected edges are stored both ways, only take them once
+				if !g.Directed && index[from] > index[e.To] {
+					continue
+				}
+				if !yield(from, e.To, e.Weight) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// pointCandidates lists every pair of points once, in the order Kruskal breaks ties
+func pointCandidates[P any, W Number](points []P, dist func(a, b P) W) func(yield func(int, int, W) bool) {
+	return func(yield func(int, int, W) bool) {
+		for i := range points {
+			for j := i + 1; j < len(points); j++ {
+				if !yield(i, j, dist(points[i], points[j])) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// KruskalMST sorts every edge of g and joins the cheapest ones that don't form a
+// cycle. ties keep insertion order. directed graphs are treated as undirected
+func KruskalMST[N comparable, W Number](g *WeightedGraph[N, W]) *MST[N, W] {
+	candidates := []MSTEdge[N, W]{}
+	graphCandidates(g)(func(from, to N, w W) bool {
+		candidates = append(candidates, MSTEdge[N, W]{From: from, To: to, Weight: w})
+		return true
+	})
+	slices.SortStableFunc(candidates, func(a, b MSTEdge[N, W]) int {
+		return cmp.Compare(a.Weight, b.Weight)
+	})
+
+	return kruskal(g.nodes, candidates)
+}
+
+// KruskalPoints runs KruskalMST over the complete graph of points, weighted by
+// dist. nodes are indexes into points
+func KruskalPoints[P any, W Number](points []P, dist func(a, b P) W) *MST[int, W] {
+	n := len(points)
+	nodes := make([]int, n)
+	candidates := make([]MSTEdge[int, W], 0, n*(n-1)/2)
+	for i := range n {
+		nodes[i] = i
+	}
+	pointCandidates(points, dist)(func(i, j int, w W) bool {
+		candidates = append(candidates, MSTEdge[int, W]{From: i, To: j, Weight: w})
+		return true
+	})
+	slices.SortStableFunc(candidates, func(a, b MSTEdge[int, W]) int {
+		return cmp.Compare(a.Weight, b.Weight)
+	})
+
+	return kruskal(nodes, candidates)
+}
+
+// rankAmongCandidates sets the Rank of each tree edge found by prim to where
+// Kruskal would have put it, without sorting the candidates. each candidate is
+// counted against the first tree edge it comes before, and a running total of
+// those counts is the rank
+func rankAmongCandidates[N comparable, W Number](m *MST[N, W], candidates func(yield func(N, N, W) bool)) {
+	type edgeKey struct {
+		from, to N
+		weight   W
+	}
+	// the position of each tree edge in the candidate list
+	treeIndex := make(map[edgeKey]int, len(m.Edges))
+	for i, e := range m.Edges {
+		treeIndex[edgeKey{e.From, e.To, e.Weight}] = i
+	}
+	pos := make([]int, len(m.Edges))
+	found := make([]bool, len(m.Edges))
+	p := 0
+	candidates(func(from, to N, w W) bool {
+		for _, key := range []edgeKey{{from, to, w}, {to, from, w}} {
+			if i, ok := treeIndex[key]; ok && !found[i] {
+				pos[i], found[i] = p, true
+			}
+		}
+		p++
+		return true
+	})
+
+	order := make([]int, len(m.Edges))
+	for i := range order {
+		order[i] = i
+	}
+	before := func(w W, p int, tree int) bool {
+		if c := cmp.Compare(w, m.Edges[tree].Weight); c != 0 {
+			return c < 0
+		}
+		return p < pos[tree]
+	}
+	slices.SortFunc(order, func(a, b int) int {
+		if before(m.Edges[a].Weight, pos[a], b) {
+			return -1
+		}
+		if before(m.Edges[b].Weight, pos[b], a) {
+			return 1
+		}
+		return 0
+	})
+
+	counts := make([]int, len(order)+1)
+	p = 0
+	candidates(func(_, _ N, w W) bool {
+		k := sort.Search(len(order), func(k int) bool { return before(w, p, order[k]) })
+		counts[k]++
+		p++
+		return true
+	})
+	rank := 0
+	for k, i := range order {
+		rank += counts[k]
+		m.Edges[i].Rank = rank
+	}
+}
+
+// PrimMST grows the tree out from the first node, always taking the cheapest edge
+// leaving it. disconnected graphs get a spanning forest. directed graphs are
+// followed along their edge directions only
+func PrimMST[N comparable, W Number](g *WeightedGraph[N, W]) *MST[N, W] {
+	m := &MST[N, W]{nodes: g.nodes}
+	inTree := make(map[N]bool, len(g.nodes))
+	components := 0
+
+	pq := &cmpHeap[MSTEdge[N, W]]{cmp: func(a, b MSTEdge[N, W]) int {
+		return cmp.Compare(a.Weight, b.Weight)
+	}}
+
+	for _, root := range g.nodes {
+		if inTree[root] {
+			continue
+		}
+		components++
+		inTree[root] = true
+		for _, e := range g.adj[root] {
+			heap.Push(pq, MSTEdge[N, W]{From: root, To: e.To, Weight: e.Weight})
+		}
+
+		for pq.Len() > 0 {
+			e := heap.Pop(pq).(MSTEdge[N, W])
+			if inTree[e.To] {
+				continue
+			}
+			inTree[e.To] = true
+			m.Edges = append(m.Edges, e)
+			m.Total += e.Weight
+			for _, next := range g.adj[e.To] {
+				if !inTree[next.To] {
+					heap.Push(pq, MSTEdge[N, W]{From: e.To, To: next.To, Weight: next.Weight})
+				}
+			}
+		}
+	}
+
+	m.Connected = components <= 1
+	rankAmongCandidates(m, graphCandidates(g))
+	return m
+}
+
+// PrimPoints runs Prim's over the complete graph of points in O(n²) without
+// building the edge list. nodes are indexes into points. ranking the tree edges
+// makes another pass over every pair, O(n² log n)
+func PrimPoints[P any, W Number](points []P, dist func(a, b P) W) *MST[int, W] {
+	n := len(points)
+	nodes := make([]int, n)
+	for i := range n {
+		nodes[i] = i
+	}
+	m := &MST[int, W]{nodes: nodes, Connected: true}
+	if n == 0 {
+		return m
+	}
+
+	// cheapest known edge from the tree to each node outside it
+	best := make([]W, n)
+	from := make([]int, n)
+	inTree := make([]bool, n)
+	inTree[0] = true
+	for j := 1; j < n; j++ {
+		best[j] = dist(points[0], points[j])
+	}
+
+	for range n - 1 {
+		next := -1
+		for j := range n {
+			if !inTree[j] && (next < 0 || best[j] < best[next]) {
+				next = j
+			}
+		}
+
+		inTree[next] = true
+		m.Edges = append(m.Edges, MSTEdge[int, W]{From: from[next], To: next, Weight: best[next]})
+		m.Total += best[next]
+		for j := range n {
+			if inTree[j] {
+				continue
+			}
+			if d := dist(points[next], points[j]); d < best[j] {
+				best[j] = d
+				from[j] = next
+			}
+		}
+	}
+
+	rankAmongCandidates(m, pointCandidates(points, dist))
+	return m
+}
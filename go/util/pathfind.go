@@ -1,25 +1,17 @@
 package util
 
 import (
-	"container/heap"
 	"math"
+	"slices"
 )
 
 type CostFunc func(grid GridInterface, from, to Coordinate) int
 
 type ValidFunc func(grid GridInterface, pos Coordinate) bool
 
-type Coordinate struct {
-	X, Y int
-}
-
-func (c Coordinate) Add(other Coordinate) Coordinate {
-	return Coordinate{X: c.X + other.X, Y: c.Y + other.Y}
-}
-
-func (c Coordinate) ManhattanDistance(other Coordinate) int {
-	return int(math.Abs(float64(c.X-other.X)) + math.Abs(float64(c.Y-other.Y)))
-}
+// Coordinate is a grid position. it's the same type as Vec2 so all the vector
+// maths works on it
+type Coordinate = Vec2
 
 // Standard 4-directional movement
 var Directions4 = []Coordinate{
@@ -288,214 +280,31 @@ func (g *SparseGrid) At(pos Coordinate) *rune {
 }
 
 // PathResult represents the result of a pathfinding operation
-type PathResult struct {
-	Found    bool
-	Path     []Coordinate
-	Cost     int
-	Visited  int
-	Distance map[Coordinate]int
-}
-
-// BFS
-func BFS(grid GridInterface, start, goal Coordinate) PathResult {
-	if start == goal {
-		return PathResult{
-			Found: true,
-			Path:  []Coordinate{start},
-			Cost:  0,
-		}
-	}
-
-	queue := []Coordinate{start}
-	visited := make(map[Coordinate]bool)
-	parent := make(map[Coordinate]Coordinate)
-	distance := make(map[Coordinate]int)
-
-	visited[start] = true
-	distance[start] = 0
-	visitedCount := 0
-
-	for len(queue) > 0 {
-		current := queue[0]
-		queue = queue[1:]
-		visitedCount++
-
-		// get path if reached goal
-		if current == goal {
-			path := []Coordinate{}
-			for pos := goal; pos != start; pos = parent[pos] {
-				path = append([]Coordinate{pos}, path...)
-			}
-			path = append([]Coordinate{start}, path...)
-
-			return PathResult{
-				Found:    true,
-				Path:     path,
-				Cost:     distance[goal],
-				Visited:  visitedCount,
-				Distance: distance,
-			}
-		}
-
-		for _, neighbor := range grid.GetNeighbors(current) {
-			if !visited[neighbor] {
-				visited[neighbor] = true
-				parent[neighbor] = current
-				distance[neighbor] = distance[current] + 1
-				queue = append(queue, neighbor)
-			}
+type PathResult = SearchResult[Coordinate]
+
+// adapts a grid to the generic search successor func
+func gridSuccessors(grid GridInterface) SuccessorFunc[Coordinate] {
+	return func(pos Coordinate) []Edge[Coordinate] {
+		neighbors := grid.GetNeighbors(pos)
+		edges := make([]Edge[Coordinate], 0, len(neighbors))
+		for _, neighbor := range neighbors {
+			edges = append(edges, Edge[Coordinate]{To: neighbor, Cost: grid.GetCost(pos, neighbor)})
 		}
-	}
-
-	return PathResult{
-		Found:    false,
-		Visited:  visitedCount,
-		Distance: distance,
+		return edges
 	}
 }
 
-type DijkstraNode struct {
-	Position Coordinate
-	Cost     int
-	Index    int // For heap operations
+func isCoordinate(goal Coordinate) GoalFunc[Coordinate] {
+	return func(pos Coordinate) bool { return pos == goal }
 }
 
-type DijkstraHeap []*DijkstraNode
-
-func (h DijkstraHeap) Len() int           { return len(h) }
-func (h DijkstraHeap) Less(i, j int) bool { return h[i].Cost < h[j].Cost }
-func (h DijkstraHeap) Swap(i, j int) {
-	h[i], h[j] = h[j], h[i]
-	h[i].Index = i
-	h[j].Index = j
-}
-
-func (h *DijkstraHeap) Push(x interface{}) {
-	n := len(*h)
-	node := x.(*DijkstraNode)
-	node.Index = n
-	*h = append(*h, node)
-}
-
-func (h *DijkstraHeap) Pop() interface{} {
-	old := *h
-	n := len(old)
-	node := old[n-1]
-	node.Index = -1
-	*h = old[0 : n-1]
-	return node
+// BFS
+func BFS(grid GridInterface, start, goal Coordinate) PathResult {
+	return SearchBFS(start, isCoordinate(goal), gridSuccessors(grid))
 }
 
 func Dijkstra(grid GridInterface, start, goal Coordinate) PathResult {
-	if start == goal {
-		return PathResult{
-			Found: true,
-			Path:  []Coordinate{start},
-			Cost:  0,
-		}
-	}
-
-	pq := &DijkstraHeap{}
-	heap.Init(pq)
-	heap.Push(pq, &DijkstraNode{Position: start, Cost: 0})
-
-	distance := make(map[Coordinate]int)
-	parent := make(map[Coordinate]Coordinate)
-	visited := make(map[Coordinate]bool)
-	visitedCount := 0
-
-	distance[start] = 0
-
-	for pq.Len() > 0 {
-		current := heap.Pop(pq).(*DijkstraNode)
-
-		if visited[current.Position] {
-			continue
-		}
-
-		visited[current.Position] = true
-		visitedCount++
-
-		// reconstruct path if goal is reached
-		if current.Position == goal {
-			path := []Coordinate{}
-			for pos := goal; pos != start; pos = parent[pos] {
-				path = append([]Coordinate{pos}, path...)
-			}
-			path = append([]Coordinate{start}, path...)
-
-			return PathResult{
-				Found:    true,
-				Path:     path,
-				Cost:     current.Cost,
-				Visited:  visitedCount,
-				Distance: distance,
-			}
-		}
-
-		for _, neighbor := range grid.GetNeighbors(current.Position) {
-			if visited[neighbor] {
-				continue
-			}
-
-			cost := grid.GetCost(current.Position, neighbor)
-			if cost < 0 {
-				continue // Invalid move
-			}
-
-			newCost := current.Cost + cost
-			if oldCost, exists := distance[neighbor]; !exists || newCost < oldCost {
-				distance[neighbor] = newCost
-				parent[neighbor] = current.Position
-				heap.Push(pq, &DijkstraNode{Position: neighbor, Cost: newCost})
-			}
-		}
-	}
-
-	return PathResult{
-		Found:    false,
-		Visited:  visitedCount,
-		Distance: distance,
-	}
-}
-
-type AStarNode struct {
-	Position Coordinate
-	GCost    int // cost from start
-	HCost    int // heuristic cost to goal
-	FCost    int // total cost (GCost + HCost)
-	Index    int // for heap operations
-}
-
-type AStarHeap []*AStarNode
-
-func (h AStarHeap) Len() int { return len(h) }
-func (h AStarHeap) Less(i, j int) bool {
-	if h[i].FCost == h[j].FCost {
-		return h[i].HCost < h[j].HCost
-	}
-	return h[i].FCost < h[j].FCost
-}
-func (h AStarHeap) Swap(i, j int) {
-	h[i], h[j] = h[j], h[i]
-	h[i].Index = i
-	h[j].Index = j
-}
-
-func (h *AStarHeap) Push(x interface{}) {
-	n := len(*h)
-	node := x.(*AStarNode)
-	node.Index = n
-	*h = append(*h, node)
-}
-
-func (h *AStarHeap) Pop() interface{} {
-	old := *h
-	n := len(old)
-	node := old[n-1]
-	node.Index = -1
-	*h = old[0 : n-1]
-	return node
+	return SearchDijkstra(start, isCoordinate(goal), gridSuccessors(grid))
 }
 
 // interface for heuristic functions
@@ -512,114 +321,93 @@ func EuclideanHeuristic(from, to Coordinate) int {
 }
 
 func AStar(grid GridInterface, start, goal Coordinate, heuristic HeuristicFunc) PathResult {
-	if start == goal {
-		return PathResult{
-			Found: true,
-			Path:  []Coordinate{start},
-			Cost:  0,
-		}
-	}
-
 	if heuristic == nil {
 		heuristic = ManhattanHeuristic
 	}
 
-	pq := &AStarHeap{}
-	heap.Init(pq)
-
-	startH := heuristic(start, goal)
-	heap.Push(pq, &AStarNode{
-		Position: start,
-		GCost:    0,
-		HCost:    startH,
-		FCost:    startH,
+	return SearchAStar(start, isCoordinate(goal), gridSuccessors(grid), func(pos Coordinate) int {
+		return heuristic(pos, goal)
 	})
+}
 
-	gScore := make(map[Coordinate]int)
-	parent := make(map[Coordinate]Coordinate)
-	visited := make(map[Coordinate]bool)
-	visitedCount := 0
-
-	gScore[start] = 0
+// MultiSourceBFS finds the fewest moves from any of starts to any cell matching goal
+func MultiSourceBFS(grid GridInterface, starts []Coordinate, goal GoalFunc[Coordinate]) PathResult {
+	return SearchBFSFrom(starts, goal, gridSuccessors(grid))
+}
 
-	for pq.Len() > 0 {
-		current := heap.Pop(pq).(*AStarNode)
+// MultiSourceDijkstra finds the cheapest path from any of starts to any cell matching goal
+func MultiSourceDijkstra(grid GridInterface, starts []Coordinate, goal GoalFunc[Coordinate]) PathResult {
+	return SearchDijkstraFrom(starts, goal, gridSuccessors(grid))
+}
 
-		if visited[current.Position] {
-			continue
-		}
+// MultiSourceAStar is MultiSourceDijkstra guided by heuristic, which must not
+// overestimate the cost to the nearest goal
+func MultiSourceAStar(grid GridInterface, starts []Coordinate, goal GoalFunc[Coordinate], heuristic func(Coordinate) int) PathResult {
+	return SearchAStarFrom(starts, goal, gridSuccessors(grid), heuristic)
+}
 
-		visited[current.Position] = true
-		visitedCount++
+// NearestSource runs a BFS out from every source at once and returns the closest
+// source for each reachable cell along with its distance. cells that are equally
+// close to more than one source are left out of nearest but are still in distance
+func NearestSource(grid GridInterface, sources []Coordinate) (map[Coordinate]Coordinate, map[Coordinate]int) {
+	queue := &Deque[Coordinate]{}
+	nearest := make(map[Coordinate]Coordinate)
+	distance := make(map[Coordinate]int)
+	tied := make(map[Coordinate]bool)
 
-		// get the path if goal is reached
-		if current.Position == goal {
-			path := []Coordinate{}
-			for pos := goal; pos != start; pos = parent[pos] {
-				path = append([]Coordinate{pos}, path...)
-			}
-			path = append([]Coordinate{start}, path...)
-
-			return PathResult{
-				Found:    true,
-				Path:     path,
-				Cost:     current.GCost,
-				Visited:  visitedCount,
-				Distance: gScore,
-			}
+	for _, src := range sources {
+		if _, seen := distance[src]; !seen {
+			distance[src] = 0
+			nearest[src] = src
+			queue.PushBack(src)
 		}
+	}
 
-		for _, neighbor := range grid.GetNeighbors(current.Position) {
-			if visited[neighbor] {
-				continue
-			}
+	for !queue.IsEmpty() {
+		current, _ := queue.PopFront()
+		owner := nearest[current]
 
-			cost := grid.GetCost(current.Position, neighbor)
-			if cost < 0 {
-				continue // invalid
+		for _, neighbor := range grid.GetNeighbors(current) {
+			dist, seen := distance[neighbor]
+			if !seen {
+				distance[neighbor] = distance[current] + 1
+				if tied[current] {
+					tied[neighbor] = true
+				} else {
+					nearest[neighbor] = owner
+				}
+				queue.PushBack(neighbor)
+				continue
 			}
 
-			tentativeG := current.GCost + cost
-			if oldG, exists := gScore[neighbor]; !exists || tentativeG < oldG {
-				gScore[neighbor] = tentativeG
-				parent[neighbor] = current.Position
-
-				h := heuristic(neighbor, goal)
-				heap.Push(pq, &AStarNode{
-					Position: neighbor,
-					GCost:    tentativeG,
-					HCost:    h,
-					FCost:    tentativeG + h,
-				})
+			// reached again at the same distance from somewhere else
+			if dist == distance[current]+1 && !tied[neighbor] && (tied[current] || nearest[neighbor] != owner) {
+				tied[neighbor] = true
+				delete(nearest, neighbor)
 			}
 		}
 	}
 
-	return PathResult{
-		Found:    false,
-		Visited:  visitedCount,
-		Distance: gScore,
-	}
+	return nearest, distance
 }
 
 // FloodFill performs a flood fill to find all reachable positions from start
 func FloodFill(grid GridInterface, start Coordinate) map[Coordinate]int {
-	queue := []Coordinate{start}
+	queue := NewDeque(start)
 	distance := make(map[Coordinate]int)
 	visited := make(map[Coordinate]bool)
 
 	distance[start] = 0
 	visited[start] = true
 
-	for len(queue) > 0 {
-		current := queue[0]
-		queue = queue[1:]
+	for !queue.IsEmpty() {
+		current, _ := queue.PopFront()
 
 		for _, neighbor := range grid.GetNeighbors(current) {
 			if !visited[neighbor] {
 				visited[neighbor] = true
 				distance[neighbor] = distance[current] + 1
-				queue = append(queue, neighbor)
+				queue.PushBack(neighbor)
 			}
 		}
 	}
@@ -629,50 +417,18 @@ func FloodFill(grid GridInterface, start Coordinate) map[Coordinate]int {
 
 // BFS but finds all shortest paths from start to goal
 func FindAllShortestPaths(grid GridInterface, start, goal Coordinate) [][]Coordinate {
-	if start == goal {
-		return [][]Coordinate{{start}}
-	}
-
-	queue := [][]Coordinate{{start}}
-	visited := make(map[Coordinate]int)
-	visited[start] = 0
-	var allPaths [][]Coordinate
-	shortestLength := -1
-
-	for len(queue) > 0 {
-		path := queue[0]
-		queue = queue[1:]
-		current := path[len(path)-1]
-
-		// if we found a shorter path before, skip longer ones
-		if shortestLength != -1 && len(path) > shortestLength {
-			continue
-		}
-
-		if current == goal {
-			if shortestLength == -1 {
-				shortestLength = len(path)
-			}
-			if len(path) == shortestLength {
-				pathCopy := make([]Coordinate, len(path))
-				copy(pathCopy, path)
-				allPaths = append(allPaths, pathCopy)
-			}
-			continue
-		}
-
-		for _, neighbor := range grid.GetNeighbors(current) {
-			if dist, exists := visited[neighbor]; !exists || dist >= len(path) {
-				visited[neighbor] = len(path)
-				newPath := make([]Coordinate, len(path)+1)
-				copy(newPath, path)
-				newPath[len(path)] = neighbor
-				queue = append(queue, newPath)
-			}
+	// every move counts as one step, same as BFS
+	next := func(pos Coordinate) []Edge[Coordinate] {
+		neighbors := grid.GetNeighbors(pos)
+		edges := make([]Edge[Coordinate], len(neighbors))
+		for i, neighbor := range neighbors {
+			edges[i] = Edge[Coordinate]{To: neighbor, Cost: 1}
 		}
+		return edges
 	}
 
-	return allPaths
+	dag := SearchDijkstraAll(start, isCoordinate(goal), next)
+	return slices.Collect(dag.Paths())
 }
 
 // Common cost function constructors for convenience
@@ -734,29 +490,3 @@ func TimeCostFunc(baseCostFunc CostFunc, timeModifier func() float64) CostFunc {
 		return int(float64(baseCost) * timeModifier())
 	}
 }
-
-type Graph[N comparable] interface {
-	Neighbors(v N) []N
-}
-
-type AdjList[K comparable] map[K][]K
-
-func (a AdjList[K]) Neighbors(v K) []K {
-	return a[v]
-}
-
-func CountAllPaths[N comparable](g Graph[N], curr, end N, memo map[N]int64) int64 {
-	if curr == end {
-		return 1
-	}
-	if count, ok := memo[curr]; ok {
-		return count
-	}
-
-	var total int64
-	for _, neigh := range g.Neighbors(curr) {
-		total += CountAllPaths(g, neigh, end, memo)
-	}
-	memo[curr] = total
-	return total
-}
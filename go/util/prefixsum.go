@@ -0,0 +1,146 @@
+package util
+
+// PrefixSum answers range sums over a slice in O(1)
+type PrefixSum struct {
+	sums []int // sums[i] is the total of the first i values
+}
+
+func NewPrefixSum(values []int) *PrefixSum {
+	return NewPrefixSumFunc(values, func(v int) int { return v })
+}
+
+// NewPrefixSumFunc sums value(v) for each item instead of the items themselves
+func NewPrefixSumFunc[T any](values []T, value func(T) int) *PrefixSum {
+	sums := make([]int, len(values)+1)
+	for i, v := range values {
+		sums[i+1] = sums[i] + value(v)
+	}
+	return &PrefixSum{sums: sums}
+}
+
+func (p *PrefixSum) Len() int { return len(p.sums) - 1 }
+
+// Sum is the total of values i through j inclusive, clipped to the slice
+func (p *PrefixSum) Sum(i, j int) int {
+	if i > j {
+		i, j = j, i
+	}
+	i, j = max(i, 0), min(j, p.Len()-1)
+	if i > j {
+		return 0
+	}
+	return p.sums[j+1] - p.sums[i]
+}
+
+// PrefixSum2D is a summed area table answering rectangle sums in O(1)
+type PrefixSum2D struct {
+	Width, Height int
+	sums          [][]int // sums[y+1][x+1] is the total of everything up to (x, y)
+}
+
+// NewPrefixSum2D sums value(cell) over rows of cells. rows are assumed to all be
+// as long as the first
+func NewPrefixSum2D[T any](cells [][]T, value func(T) int) *PrefixSum2D {
+	height := len(cells)
+	width := 0
+	if height > 0 {
+		width = len(cells[0])
+	}
+
+	sums := make([][]int, height+1)
+	sums[0] = make([]int, width+1)
+	for y, row := range cells {
+		sums[y+1] = make([]int, width+1)
+		for x, cell := range row {
+			sums[y+1][x+1] = value(cell) + sums[y][x+1] + sums[y+1][x] - sums[y][x]
+		}
+	}
+	return &PrefixSum2D{Width: width, Height: height, sums: sums}
+}
+
+// PrefixSum2DFromGrid builds a table over a grid, e.g. counting free cells with
+// func(r rune) int { if r == '.' { return 1 }; return 0 }
+func PrefixSum2DFromGrid(g *DenseGrid, value func(rune) int) *PrefixSum2D {
+	return NewPrefixSum2D(g.Grid, value)
+}
+
+// RectSum is the total over the rectangle with opposite corners (x0, y0) and
+// (x1, y1), inclusive. parts outside the grid count as zero
+func (p *PrefixSum2D) RectSum(x0, y0, x1, y1 int) int {
+	if x0 > x1 {
+		x0, x1 = x1, x0
+	}
+	if y0 > y1 {
+		y0, y1 = y1, y0
+	}
+	x0, y0 = max(x0, 0), max(y0, 0)
+	x1, y1 = min(x1, p.Width-1), min(y1, p.Height-1)
+	if x0 > x1 || y0 > y1 {
+		return 0
+	}
+
+	s := p.sums
+	return s[y1+1][x1+1] - s[y0][x1+1] - s[y1+1][x0] + s[y0][x0]
+}
+
+func (p *PrefixSum2D) Total() int {
+	return p.sums[p.Height][p.Width]
+}
+
+// DiffArray2D collects lots of rectangle increments cheaply and applies them all
+// at once with Build
+type DiffArray2D struct {
+	Width, Height int
+	diff          [][]int // one bigger each way so the far corners always fit
+}
+
+func NewDiffArray2D(width, height int) *DiffArray2D {
+	diff := make([][]int, height+1)
+	for y := range diff {
+		diff[y] = make([]int, width+1)
+	}
+	return &DiffArray2D{Width: width, Height: height, diff: diff}
+}
+
+// AddRect adds delta to every cell in the rectangle with opposite corners
+// (x0, y0) and (x1, y1), inclusive. parts outside the grid are ignored
+func (d *DiffArray2D) AddRect(x0, y0, x1, y1, delta int) {
+	if x0 > x1 {
+		x0, x1 = x1, x0
+	}
+	if y0 > y1 {
+		y0, y1 = y1, y0
+	}
+	x0, y0 = max(x0, 0), max(y0, 0)
+	x1, y1 = min(x1, d.Width-1), min(y1, d.Height-1)
+	if x0 > x1 || y0 > y1 {
+		return
+	}
+
+	d.diff[y0][x0] += delta
+	d.diff[y0][x1+1] -= delta
+	d.diff[y1+1][x0] -= delta
+	d.diff[y1+1][x1+1] += delta
+}
+
+// Build returns the value of every cell after all the increments so far
+func (d *DiffArray2D) Build() [][]int {
+	out := make([][]int, d.Height)
+	for y := range out {
+		out[y] = make([]int, d.Width)
+		for x := range out[y] {
+			v := d.diff[y][x]
+			if x > 0 {
+				v += out[y][x-1]
+			}
+			if y > 0 {
+				v += out[y-1][x]
+			}
+			if x > 0 && y > 0 {
+				v -= out[y-1][x-1]
+			}
+			out[y][x] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,99 @@
+package util
+
+import (
+	"iter"
+	"slices"
+)
+
+// Ring is a circular list with a cursor, like a dial. moving the cursor is O(1)
+// however far it goes. inserting and removing shift the items along, so they're
+// O(n)
+type Ring[T any] struct {
+	items  []T
+	cursor int
+}
+
+// NewRing returns a ring of a copy of items with the cursor on the first
+func NewRing[T any](items ...T) *Ring[T] {
+	return &Ring[T]{items: slices.Clone(items)}
+}
+
+func (r *Ring[T]) Len() int { return len(r.items) }
+
+// Cursor is the index the cursor is on
+func (r *Ring[T]) Cursor() int { return r.cursor }
+
+// SetCursor moves the cursor to index i, wrapping round if needed
+func (r *Ring[T]) SetCursor(i int) {
+	if len(r.items) > 0 {
+		r.cursor = mod(i, len(r.items))
+	}
+}
+
+// Current is the item under the cursor. it panics on an empty ring
+func (r *Ring[T]) Current() T { return r.items[r.cursor] }
+
+// At is the item i steps on from the cursor, or back for negative i
+func (r *Ring[T]) At(i int) T { return r.items[mod(r.cursor+i, len(r.items))] }
+
+// Rotate moves the cursor n steps forward, or back for negative n, and returns
+// how many of those steps landed on index 0, counting the one it stops on
+func (r *Ring[T]) Rotate(n int) int {
+	size := len(r.items)
+	if size == 0 {
+		return 0
+	}
+
+	zeros := 0
+	if n > 0 {
+		zeros = (r.cursor + n) / size
+	} else if m := -n; m > 0 {
+		// steps k in 1..m with cursor-k a multiple of size
+		first := r.cursor
+		if first == 0 {
+			first = size
+		}
+		if m >= first {
+			zeros = (m-first)/size + 1
+		}
+	}
+
+	r.cursor = mod(r.cursor+n, size)
+	return zeros
+}
+
+// Insert puts item under the cursor, pushing the current item and everything
+// after it one index on
+func (r *Ring[T]) Insert(item T) {
+	var zero T
+	r.items = append(r.items, zero)
+	copy(r.items[r.cursor+1:], r.items[r.cursor:])
+	r.items[r.cursor] = item
+}
+
+// Remove takes out the item under the cursor and returns it. the item after it
+// takes its place under the cursor. it panics on an empty ring
+func (r *Ring[T]) Remove() T {
+	item := r.items[r.cursor]
+	r.items = append(r.items[:r.cursor], r.items[r.cursor+1:]...)
+	if r.cursor == len(r.items) {
+		r.cursor = 0
+	}
+	return item
+}
+
+// All iterates over the items in index order, starting from index 0
+func (r *Ring[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, item := range r.items {
+			if !yield(i, item) {
+				return
+			}
+		}
+	}
+}
+
+// mod is x % n but always in [0, n)
+func mod(x, n int) int {
+	return ((x % n) + n) % n
+}
@@ -0,0 +1,205 @@
+package util
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Edge is a transition to another state along with the cost of taking it
+type Edge[S comparable] struct {
+	To   S
+	Cost int
+}
+
+// SuccessorFunc returns the states reachable in one move from s
+type SuccessorFunc[S comparable] func(s S) []Edge[S]
+
+// GoalFunc reports whether s is a goal state
+type GoalFunc[S comparable] func(s S) bool
+
+// SearchResult is the generic version of PathResult
+type SearchResult[S comparable] struct {
+	Found    bool
+	Source   S // the start the path came from
+	Path     []S
+	Cost     int
+	Visited  int
+	Distance map[S]int
+}
+
+// walks parent links back from end and returns the path in start -> end order.
+// starts are their own parent
+func buildPath[S comparable](parent map[S]S, end S) []S {
+	path := []S{end}
+	for pos := end; parent[pos] != pos; {
+		pos = parent[pos]
+		path = append(path, pos)
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// SearchBFS finds the path with the fewest moves. edge costs are ignored
+func SearchBFS[S comparable](start S, goal GoalFunc[S], next SuccessorFunc[S]) SearchResult[S] {
+	return SearchBFSFrom([]S{start}, goal, next)
+}
+
+// SearchBFSFrom is SearchBFS with every start seeded at distance 0
+func SearchBFSFrom[S comparable](starts []S, goal GoalFunc[S], next SuccessorFunc[S]) SearchResult[S] {
+	for _, start := range starts {
+		if goal(start) {
+			return SearchResult[S]{
+				Found:  true,
+				Source: start,
+				Path:   []S{start},
+				Cost:   0,
+			}
+		}
+	}
+
+	queue := &Deque[S]{}
+	parent := make(map[S]S)
+	distance := make(map[S]int)
+
+	for _, start := range starts {
+		if _, seen := distance[start]; !seen {
+			distance[start] = 0
+			parent[start] = start
+			queue.PushBack(start)
+		}
+	}
+	visitedCount := 0
+
+	for !queue.IsEmpty() {
+		current, _ := queue.PopFront()
+		visitedCount++
+
+		if goal(current) {
+			path := buildPath(parent, current)
+			return SearchResult[S]{
+				Found:    true,
+				Source:   path[0],
+				Path:     path,
+				Cost:     distance[current],
+				Visited:  visitedCount,
+				Distance: distance,
+			}
+		}
+
+		for _, e := range next(current) {
+			if _, seen := distance[e.To]; !seen {
+				parent[e.To] = current
+				distance[e.To] = distance[current] + 1
+				queue.PushBack(e.To)
+			}
+		}
+	}
+
+	return SearchResult[S]{
+		Found:    false,
+		Visited:  visitedCount,
+		Distance: distance,
+	}
+}
+
+// SearchDijkstra finds the cheapest path. negative edge costs are skipped
+func SearchDijkstra[S comparable](start S, goal GoalFunc[S], next SuccessorFunc[S]) SearchResult[S] {
+	return SearchAStarFrom([]S{start}, goal, next, nil)
+}
+
+// SearchDijkstraFrom is SearchDijkstra with every start seeded at cost 0
+func SearchDijkstraFrom[S comparable](starts []S, goal GoalFunc[S], next SuccessorFunc[S]) SearchResult[S] {
+	return SearchAStarFrom(starts, goal, next, nil)
+}
+
+// SearchAStar is SearchDijkstra guided by heuristic, which estimates the
+// remaining cost from a state to the goal. a nil heuristic behaves like Dijkstra
+func SearchAStar[S comparable](start S, goal GoalFunc[S], next SuccessorFunc[S], heuristic func(S) int) SearchResult[S] {
+	return SearchAStarFrom([]S{start}, goal, next, heuristic)
+}
+
+// SearchAStarFrom is SearchAStar with every start seeded at cost 0
+func SearchAStarFrom[S comparable](starts []S, goal GoalFunc[S], next SuccessorFunc[S], heuristic func(S) int) SearchResult[S] {
+	for _, start := range starts {
+		if goal(start) {
+			return SearchResult[S]{
+				Found:  true,
+				Source: start,
+				Path:   []S{start},
+				Cost:   0,
+			}
+		}
+	}
+
+	if heuristic == nil {
+		heuristic = func(S) int { return 0 }
+	}
+
+	pq := NewIndexedMinHeapFunc[S](compareSearchPriority)
+	gScore := make(map[S]int)
+	parent := make(map[S]S)
+	closed := make(map[S]bool)
+	visitedCount := 0
+
+	for _, start := range starts {
+		if _, seen := gScore[start]; seen {
+			continue
+		}
+		gScore[start] = 0
+		parent[start] = start
+		startH := heuristic(start)
+		pq.Upsert(start, searchPriority{FCost: startH, HCost: startH})
+	}
+
+	for pq.Len() > 0 {
+		current, _ := pq.PopMin()
+		closed[current] = true
+		visitedCount++
+
+		if goal(current) {
+			path := buildPath(parent, current)
+			return SearchResult[S]{
+				Found:    true,
+				Source:   path[0],
+				Path:     path,
+				Cost:     gScore[current],
+				Visited:  visitedCount,
+				Distance: gScore,
+			}
+		}
+
+		for _, e := range next(current) {
+			if closed[e.To] || e.Cost < 0 {
+				continue
+			}
+
+			tentativeG := gScore[current] + e.Cost
+			if oldG, exists := gScore[e.To]; !exists || tentativeG < oldG {
+				gScore[e.To] = tentativeG
+				parent[e.To] = current
+
+				h := heuristic(e.To)
+				pq.Upsert(e.To, searchPriority{FCost: tentativeG + h, HCost: h})
+			}
+		}
+	}
+
+	return SearchResult[S]{
+		Found:    false,
+		Visited:  visitedCount,
+		Distance: gScore,
+	}
+}
+
+// queue priority for the searches. ties on FCost go to the state closer to the goal
+type searchPriority struct {
+	FCost int // total cost (cost from start + HCost)
+	HCost int // heuristic cost to goal
+}
+
+func compareSearchPriority(a, b searchPriority) int {
+	if a.FCost != b.FCost {
+		return cmp.Compare(a.FCost, b.FCost)
+	}
+	return cmp.Compare(a.HCost, b.HCost)
+}
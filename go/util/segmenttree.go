@@ -0,0 +1,176 @@
+package util
+
+// Monoid says how a SegmentTree combines values
+type Monoid[T Number] struct {
+	Identity T
+	Combine  func(a, b T) T
+	// Scale is what one value spread over n elements adds up to. v*n for sums, v
+	// for min and max. Assign needs it
+	Scale func(v T, n int) T
+	// AddTo is what an aggregate of n elements becomes when delta is added to each
+	// of them. agg + delta*n for sums, agg + delta for min and max. Add needs it,
+	// and it's nil for monoids like product or xor where there's no such rule
+	AddTo func(agg, delta T, n int) T
+}
+
+func SumMonoid[T Number]() Monoid[T] {
+	return Monoid[T]{
+		Identity: 0,
+		Combine:  func(a, b T) T { return a + b },
+		Scale:    func(v T, n int) T { return v * T(n) },
+		AddTo:    func(agg, delta T, n int) T { return agg + delta*T(n) },
+	}
+}
+
+// MinMonoid needs a value bigger than anything in the tree, e.g. math.MaxInt
+func MinMonoid[T Number](inf T) Monoid[T] {
+	return Monoid[T]{
+		Identity: inf,
+		Combine:  func(a, b T) T { return min(a, b) },
+		Scale:    func(v T, n int) T { return v },
+		AddTo:    func(agg, delta T, n int) T { return agg + delta },
+	}
+}
+
+// MaxMonoid needs a value smaller than anything in the tree, e.g. math.MinInt
+func MaxMonoid[T Number](negInf T) Monoid[T] {
+	return Monoid[T]{
+		Identity: negInf,
+		Combine:  func(a, b T) T { return max(a, b) },
+		Scale:    func(v T, n int) T { return v },
+		AddTo:    func(agg, delta T, n int) T { return agg + delta },
+	}
+}
+
+// pending update on a node. an assign wipes out any add before it and an add
+// after an assign just changes the assigned value, so only one is ever set
+type segmentTag[T Number] struct {
+	assign    bool
+	value     T // the assigned value, or the amount to add
+	hasUpdate bool
+}
+
+// SegmentTree answers range queries under range add and range assign, all in
+// O(log n). ranges are half open like Interval
+type SegmentTree[T Number] struct {
+	n      int
+	monoid Monoid[T]
+	agg    []T
+	lazy   []segmentTag[T]
+}
+
+func NewSegmentTree[T Number](values []T, monoid Monoid[T]) *SegmentTree[T] {
+	t := &SegmentTree[T]{
+		n:      len(values),
+		monoid: monoid,
+		agg:    make([]T, 4*max(len(values), 1)),
+		lazy:   make([]segmentTag[T], 4*max(len(values), 1)),
+	}
+	if t.n > 0 {
+		t.build(1, 0, t.n, values)
+	}
+	return t
+}
+
+func (t *SegmentTree[T]) build(node, lo, hi int, values []T) {
+	if hi-lo == 1 {
+		t.agg[node] = values[lo]
+		return
+	}
+	mid := (lo + hi) / 2
+	t.build(2*node, lo, mid, values)
+	t.build(2*node+1, mid, hi, values)
+	t.agg[node] = t.monoid.Combine(t.agg[2*node], t.agg[2*node+1])
+}
+
+func (t *SegmentTree[T]) Len() int { return t.n }
+
+// applies tag to node, which covers size elements
+func (t *SegmentTree[T]) apply(node, size int, tag segmentTag[T]) {
+	cur := &t.lazy[node]
+	if tag.assign {
+		t.agg[node] = t.monoid.Scale(tag.value, size)
+		*cur = tag
+		return
+	}
+
+	// pending adds still stack up with + since they apply to each element
+	t.agg[node] = t.monoid.AddTo(t.agg[node], tag.value, size)
+	if cur.hasUpdate {
+		cur.value += tag.value
+	} else {
+		*cur = tag
+	}
+}
+
+func (t *SegmentTree[T]) push(node, lo, hi int) {
+	if !t.lazy[node].hasUpdate {
+		return
+	}
+	mid := (lo + hi) / 2
+	t.apply(2*node, mid-lo, t.lazy[node])
+	t.apply(2*node+1, hi-mid, t.lazy[node])
+	t.lazy[node] = segmentTag[T]{}
+}
+
+func (t *SegmentTree[T]) update(node, lo, hi, l, r int, tag segmentTag[T]) {
+	if r <= lo || hi <= l {
+		return
+	}
+	if l <= lo && hi <= r {
+		t.apply(node, hi-lo, tag)
+		return
+	}
+	t.push(node, lo, hi)
+	mid := (lo + hi) / 2
+	t.update(2*node, lo, mid, l, r, tag)
+	t.update(2*node+1, mid, hi, l, r, tag)
+	t.agg[node] = t.monoid.Combine(t.agg[2*node], t.agg[2*node+1])
+}
+
+// Add adds delta to every element in [l, r). it panics if the monoid has no AddTo
+func (t *SegmentTree[T]) Add(l, r int, delta T) {
+	if t.monoid.AddTo == nil {
+		panic("segment tree: monoid has no AddTo, so Add isn't supported")
+	}
+	if l < r && t.n > 0 {
+		t.update(1, 0, t.n, l, r, segmentTag[T]{value: delta, hasUpdate: true})
+	}
+}
+
+// Assign sets every element in [l, r) to value
+func (t *SegmentTree[T]) Assign(l, r int, value T) {
+	if l < r && t.n > 0 {
+		t.update(1, 0, t.n, l, r, segmentTag[T]{assign: true, value: value, hasUpdate: true})
+	}
+}
+
+// Set changes the single element i
+func (t *SegmentTree[T]) Set(i int, value T) {
+	t.Assign(i, i+1, value)
+}
+
+func (t *SegmentTree[T]) query(node, lo, hi, l, r int) T {
+	if r <= lo || hi <= l {
+		return t.monoid.Identity
+	}
+	if l <= lo && hi <= r {
+		return t.agg[node]
+	}
+	t.push(node, lo, hi)
+	mid := (lo + hi) / 2
+	return t.monoid.Combine(t.query(2*node, lo, mid, l, r), t.query(2*node+1, mid, hi, l, r))
+}
+
+// Query combines the elements in [l, r). an empty range gives the identity
+func (t *SegmentTree[T]) Query(l, r int) T {
+	if l >= r || t.n == 0 {
+		return t.monoid.Identity
+	}
+	return t.query(1, 0, t.n, l, r)
+}
+
+// Get returns the single element i
+func (t *SegmentTree[T]) Get(i int) T {
+	return t.Query(i, i+1)
+}
@@ -0,0 +1,135 @@
+package util
+
+import (
+	"cmp"
+	"container/heap"
+	"iter"
+	"slices"
+)
+
+type topKItem[T any] struct {
+	value T
+	seq   uint64
+}
+
+// heap with the worst kept item at the root so it can be evicted
+type topKHeap[T any] struct {
+	items []topKItem[T]
+	less  func(a, b T) bool
+}
+
+func (h *topKHeap[T]) Len() int { return len(h.items) }
+
+func (h *topKHeap[T]) Less(i, j int) bool {
+	return h.worse(h.items[i], h.items[j])
+}
+
+func (h *topKHeap[T]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+}
+
+func (h *topKHeap[T]) Push(x any) {
+	h.items = append(h.items, x.(topKItem[T]))
+}
+
+func (h *topKHeap[T]) Pop() any {
+	n := len(h.items)
+	item := h.items[n-1]
+	h.items = h.items[:n-1]
+	return item
+}
+
+// later items lose ties so results are stable
+func (h *topKHeap[T]) worse(a, b topKItem[T]) bool {
+	if h.less(b.value, a.value) {
+		return true
+	}
+	if h.less(a.value, b.value) {
+		return false
+	}
+	return a.seq > b.seq
+}
+
+// TopK keeps only the k best items out of everything added, in O(log k) per item
+// and O(k) memory. a is better than b when less(a, b)
+type TopK[T any] struct {
+	k    int
+	heap *topKHeap[T]
+	seq  uint64
+}
+
+func NewTopK[T any](k int, less func(a, b T) bool) *TopK[T] {
+	return &TopK[T]{
+		k:    k,
+		heap: &topKHeap[T]{items: make([]topKItem[T], 0, max(k, 0)), less: less},
+	}
+}
+
+// Add offers x, keeping it only if it's among the k best so far
+func (t *TopK[T]) Add(x T) {
+	if t.k <= 0 {
+		return
+	}
+	item := topKItem[T]{value: x, seq: t.seq}
+	t.seq++
+
+	if t.heap.Len() < t.k {
+		heap.Push(t.heap, item)
+		return
+	}
+	if t.heap.worse(t.heap.items[0], item) {
+		t.heap.items[0] = item
+		heap.Fix(t.heap, 0)
+	}
+}
+
+func (t *TopK[T]) Len() int { return t.heap.Len() }
+
+// Worst returns the item that would be evicted next, once k items are kept
+func (t *TopK[T]) Worst() (T, bool) {
+	if t.heap.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return t.heap.items[0].value, true
+}
+
+// Items returns the kept items, best first
+func (t *TopK[T]) Items() []T {
+	items := slices.Clone(t.heap.items)
+	slices.SortFunc(items, func(a, b topKItem[T]) int {
+		if t.heap.worse(b, a) {
+			return -1
+		}
+		if t.heap.worse(a, b) {
+			return 1
+		}
+		return 0
+	})
+
+	out := make([]T, len(items))
+	for i, item := range items {
+		out[i] = item.value
+	}
+	return out
+}
+
+// SmallestK returns the k smallest values of seq in ascending order
+func SmallestK[T cmp.Ordered](seq iter.Seq[T], k int) []T {
+	return SmallestKFunc(seq, k, cmp.Less[T])
+}
+
+// SmallestKFunc returns the k smallest values of seq by less, smallest first.
+// equal values keep the order they came in
+func SmallestKFunc[T any](seq iter.Seq[T], k int, less func(a, b T) bool) []T {
+	top := NewTopK(k, less)
+	for v := range seq {
+		top.Add(v)
+	}
+	return top.Items()
+}
+
+// LargestK returns the k largest values of seq in descending order
+func LargestK[T cmp.Ordered](seq iter.Seq[T], k int) []T {
+	return SmallestKFunc(seq, k, func(a, b T) bool { return a > b })
+}
@@ -0,0 +1,111 @@
+package util
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func sign(x int) int {
+	switch {
+	case x < 0:
+		return -1
+	case x > 0:
+		return 1
+	}
+	return 0
+}
+
+// Vec2 is an integer 2D vector. y grows downwards, like grid rows
+type Vec2 struct {
+	X, Y int
+}
+
+func (v Vec2) Add(o Vec2) Vec2   { return Vec2{X: v.X + o.X, Y: v.Y + o.Y} }
+func (v Vec2) Sub(o Vec2) Vec2   { return Vec2{X: v.X - o.X, Y: v.Y - o.Y} }
+func (v Vec2) Scale(k int) Vec2  { return Vec2{X: v.X * k, Y: v.Y * k} }
+func (v Vec2) Neg() Vec2         { return Vec2{X: -v.X, Y: -v.Y} }
+func (v Vec2) Dot(o Vec2) int    { return v.X*o.X + v.Y*o.Y }
+func (v Vec2) Abs() Vec2         { return Vec2{X: abs(v.X), Y: abs(v.Y)} }
+func (v Vec2) Sign() Vec2        { return Vec2{X: sign(v.X), Y: sign(v.Y)} }
+func (v Vec2) Min(o Vec2) Vec2   { return Vec2{X: min(v.X, o.X), Y: min(v.Y, o.Y)} }
+func (v Vec2) Max(o Vec2) Vec2   { return Vec2{X: max(v.X, o.X), Y: max(v.Y, o.Y)} }
+func (v Vec2) Dims() int         { return 2 }
+func (v Vec2) RotateRight() Vec2 { return Vec2{X: -v.Y, Y: v.X} }
+func (v Vec2) RotateLeft() Vec2  { return Vec2{X: v.Y, Y: -v.X} }
+
+// Cross is the z component of the 3D cross product. with y down, positive means
+// o is clockwise of v on screen
+func (v Vec2) Cross(o Vec2) int { return v.X*o.Y - v.Y*o.X }
+
+// Axis returns X for 0 and Y otherwise, so a Vec2 can go in a geom.KDTree
+func (v Vec2) Axis(i int) int {
+	if i == 0 {
+		return v.X
+	}
+	return v.Y
+}
+
+func (v Vec2) ManhattanDistance(o Vec2) int {
+	return abs(v.X-o.X) + abs(v.Y-o.Y)
+}
+
+// ChebyshevDistance is the number of king moves between v and o
+func (v Vec2) ChebyshevDistance(o Vec2) int {
+	return max(abs(v.X-o.X), abs(v.Y-o.Y))
+}
+
+// DistanceSq is the squared euclidean distance, exact unlike taking the root
+func (v Vec2) DistanceSq(o Vec2) int {
+	d := v.Sub(o)
+	return d.Dot(d)
+}
+
+// Vec3 is an integer 3D vector
+type Vec3 struct {
+	X, Y, Z int
+}
+
+func (v Vec3) Add(o Vec3) Vec3  { return Vec3{X: v.X + o.X, Y: v.Y + o.Y, Z: v.Z + o.Z} }
+func (v Vec3) Sub(o Vec3) Vec3  { return Vec3{X: v.X - o.X, Y: v.Y - o.Y, Z: v.Z - o.Z} }
+func (v Vec3) Scale(k int) Vec3 { return Vec3{X: v.X * k, Y: v.Y * k, Z: v.Z * k} }
+func (v Vec3) Neg() Vec3        { return Vec3{X: -v.X, Y: -v.Y, Z: -v.Z} }
+func (v Vec3) Dot(o Vec3) int   { return v.X*o.X + v.Y*o.Y + v.Z*o.Z }
+func (v Vec3) Abs() Vec3        { return Vec3{X: abs(v.X), Y: abs(v.Y), Z: abs(v.Z)} }
+func (v Vec3) Sign() Vec3       { return Vec3{X: sign(v.X), Y: sign(v.Y), Z: sign(v.Z)} }
+func (v Vec3) Min(o Vec3) Vec3  { return Vec3{X: min(v.X, o.X), Y: min(v.Y, o.Y), Z: min(v.Z, o.Z)} }
+func (v Vec3) Max(o Vec3) Vec3  { return Vec3{X: max(v.X, o.X), Y: max(v.Y, o.Y), Z: max(v.Z, o.Z)} }
+func (v Vec3) Dims() int        { return 3 }
+
+func (v Vec3) Cross(o Vec3) Vec3 {
+	return Vec3{
+		X: v.Y*o.Z - v.Z*o.Y,
+		Y: v.Z*o.X - v.X*o.Z,
+		Z: v.X*o.Y - v.Y*o.X,
+	}
+}
+
+// Axis returns X, Y, Z for 0, 1, 2, so a Vec3 can go in a geom.KDTree
+func (v Vec3) Axis(i int) int {
+	switch i {
+	case 0:
+		return v.X
+	case 1:
+		return v.Y
+	}
+	return v.Z
+}
+
+func (v Vec3) ManhattanDistance(o Vec3) int {
+	return abs(v.X-o.X) + abs(v.Y-o.Y) + abs(v.Z-o.Z)
+}
+
+func (v Vec3) ChebyshevDistance(o Vec3) int {
+	return max(abs(v.X-o.X), abs(v.Y-o.Y), abs(v.Z-o.Z))
+}
+
+func (v Vec3) DistanceSq(o Vec3) int {
+	d := v.Sub(o)
+	return d.Dot(d)
+}
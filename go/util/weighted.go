@@ -0,0 +1,313 @@
+package util
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Number is any type usable as an edge weight
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// WeightedPathResult is PathResult for graphs whose weights aren't ints
+type WeightedPathResult[N comparable, W Number] struct {
+	Found    bool
+	Source   N
+	Path     []N
+	Cost     W
+	Visited  int
+	Distance map[N]W
+}
+
+type WeightedEdge[N comparable, W Number] struct {
+	To     N
+	Weight W
+}
+
+// WeightedGraph is an adjacency list with a weight on every edge. it keeps nodes
+// in insertion order so anything iterating over them is deterministic
+type WeightedGraph[N comparable, W Number] struct {
+	Directed bool
+	adj      map[N][]WeightedEdge[N, W]
+	nodes    []N
+}
+
+func NewWeightedGraph[N comparable, W Number](directed bool) *WeightedGraph[N, W] {
+	return &WeightedGraph[N, W]{
+		Directed: directed,
+		adj:      make(map[N][]WeightedEdge[N, W]),
+	}
+}
+
+// AddNode adds n with no edges. adding an existing node does nothing
+func (g *WeightedGraph[N, W]) AddNode(n N) {
+	if _, ok := g.adj[n]; !ok {
+		g.adj[n] = nil
+		g.nodes = append(g.nodes, n)
+	}
+}
+
+// AddEdge adds an edge from -> to, and to -> from as well if the graph is undirected
+func (g *WeightedGraph[N, W]) AddEdge(from, to N, weight W) {
+	g.AddNode(from)
+	g.AddNode(to)
+	g.adj[from] = append(g.adj[from], WeightedEdge[N, W]{To: to, Weight: weight})
+	if !g.Directed && from != to {
+		g.adj[to] = append(g.adj[to], WeightedEdge[N, W]{To: from, Weight: weight})
+	}
+}
+
+// Edges returns the outgoing edges of n
+func (g *WeightedGraph[N, W]) Edges(n N) []WeightedEdge[N, W] {
+	return g.adj[n]
+}
+
+func (g *WeightedGraph[N, W]) Neighbors(n N) []N {
+	edges := g.adj[n]
+	neighbors := make([]N, len(edges))
+	for i, e := range edges {
+		neighbors[i] = e.To
+	}
+	return neighbors
+}
+
+// Nodes returns every node in insertion order
+func (g *WeightedGraph[N, W]) Nodes() []N {
+	return g.nodes
+}
+
+// Dijkstra finds the cheapest path from start to goal. weights must not be negative
+func (g *WeightedGraph[N, W]) Dijkstra(start, goal N) WeightedPathResult[N, W] {
+	pq := NewIndexedMinHeap[N, W]()
+	pq.Upsert(start, 0)
+
+	distance := map[N]W{start: 0}
+	parent := map[N]N{start: start}
+	closed := make(map[N]bool)
+	visitedCount := 0
+
+	for pq.Len() > 0 {
+		current, dist := pq.PopMin()
+		closed[current] = true
+		visitedCount++
+
+		if current == goal {
+			return WeightedPathResult[N, W]{
+				Found:    true,
+				Source:   start,
+				Path:     buildPath(parent, goal),
+				Cost:     dist,
+				Visited:  visitedCount,
+				Distance: distance,
+			}
+		}
+
+		for _, e := range g.adj[current] {
+			if closed[e.To] {
+				continue
+			}
+			newDist := dist + e.Weight
+			if old, exists := distance[e.To]; !exists || newDist < old {
+				distance[e.To] = newDist
+				parent[e.To] = current
+				pq.Upsert(e.To, newDist)
+			}
+		}
+	}
+
+	return WeightedPathResult[N, W]{
+		Found:    false,
+		Visited:  visitedCount,
+		Distance: distance,
+	}
+}
+
+// BellmanFord finds the cheapest path from start to goal and allows negative
+// weights. returns a *CycleError if a negative cycle is reachable from start.
+// on an undirected graph any negative edge is a negative cycle
+func (g *WeightedGraph[N, W]) BellmanFord(start, goal N) (WeightedPathResult[N, W], error) {
+	distance := map[N]W{start: 0}
+	parent := map[N]N{start: start}
+
+	// relax every edge once, reporting the last node that got cheaper
+	relax := func() (N, bool) {
+		var changed N
+		updated := false
+		for _, u := range g.nodes {
+			du, ok := distance[u]
+			if !ok {
+				continue
+			}
+			for _, e := range g.adj[u] {
+				if old, exists := distance[e.To]; !exists || du+e.Weight < old {
+					distance[e.To] = du + e.Weight
+					parent[e.To] = u
+					changed, updated = e.To, true
+				}
+			}
+		}
+		return changed, updated
+	}
+
+	for range len(g.nodes) - 1 {
+		if _, updated := relax(); !updated {
+			break
+		}
+	}
+
+	if changed, updated := relax(); updated {
+		// walking back n times from anything still improving lands inside the cycle
+		for range len(g.nodes) {
+			changed = parent[changed]
+		}
+		cycle := []N{changed}
+		for n := parent[changed]; n != changed; n = parent[n] {
+			cycle = append(cycle, n)
+		}
+		cycle = append(cycle, changed)
+		slices.Reverse(cycle)
+		return WeightedPathResult[N, W]{}, fmt.Errorf("negative weight cycle: %w", &CycleError[N]{Cycle: cycle})
+	}
+
+	if _, ok := distance[goal]; !ok {
+		return WeightedPathResult[N, W]{
+			Found:    false,
+			Visited:  len(distance),
+			Distance: distance,
+		}, nil
+	}
+
+	return WeightedPathResult[N, W]{
+		Found:    true,
+		Source:   start,
+		Path:     buildPath(parent, goal),
+		Cost:     distance[goal],
+		Visited:  len(distance),
+		Distance: distance,
+	}, nil
+}
+
+// AllPairs holds the shortest distance between every pair of nodes
+type AllPairs[N comparable, W Number] struct {
+	index map[N]int
+	nodes []N
+	dist  [][]W
+	reach [][]bool
+	next  [][]int // next hop on the shortest path from i to j
+}
+
+// FloydWarshall computes shortest distances between every pair of nodes in O(n³).
+// negative weights are allowed. returns a *CycleError like BellmanFord if there's
+// a negative cycle
+func (g *WeightedGraph[N, W]) FloydWarshall() (*AllPairs[N, W], error) {
+	n := len(g.nodes)
+	ap := &AllPairs[N, W]{
+		index: make(map[N]int, n),
+		nodes: g.nodes,
+		dist:  make([][]W, n),
+		reach: make([][]bool, n),
+		next:  make([][]int, n),
+	}
+	for i, node := range g.nodes {
+		ap.index[node] = i
+		ap.dist[i] = make([]W, n)
+		ap.reach[i] = make([]bool, n)
+		ap.next[i] = make([]int, n)
+		ap.reach[i][i] = true
+		ap.next[i][i] = i
+	}
+
+	for i, node := range g.nodes {
+		for _, e := range g.adj[node] {
+			j := ap.index[e.To]
+			if !ap.reach[i][j] || e.Weight < ap.dist[i][j] {
+				ap.dist[i][j] = e.Weight
+				ap.reach[i][j] = true
+				ap.next[i][j] = j
+			}
+		}
+	}
+
+	for k := range n {
+		for i := range n {
+			if !ap.reach[i][k] {
+				continue
+			}
+			for j := range n {
+				if !ap.reach[k][j] {
+					continue
+				}
+				through := ap.dist[i][k] + ap.dist[k][j]
+				if !ap.reach[i][j] || through < ap.dist[i][j] {
+					ap.dist[i][j] = through
+					ap.reach[i][j] = true
+					ap.next[i][j] = ap.next[i][k]
+				}
+			}
+		}
+	}
+
+	for i := range n {
+		if ap.dist[i][i] < 0 {
+			// next only stays trustworthy without negative cycles, so let bellman-ford
+			// name the cycle. starting from a node on it guarantees it's found
+			_, err := g.BellmanFord(g.nodes[i], g.nodes[i])
+			return nil, err
+		}
+	}
+
+	return ap, nil
+}
+
+// Distance returns the shortest distance from -> to and whether to is reachable
+func (a *AllPairs[N, W]) Distance(from, to N) (W, bool) {
+	i, ok1 := a.index[from]
+	j, ok2 := a.index[to]
+	if !ok1 || !ok2 || !a.reach[i][j] {
+		var zero W
+		return zero, false
+	}
+	return a.dist[i][j], true
+}
+
+// Path returns the shortest path from -> to. Distance holds the distances from from
+func (a *AllPairs[N, W]) Path(from, to N) WeightedPathResult[N, W] {
+	i, ok1 := a.index[from]
+	j, ok2 := a.index[to]
+	if !ok1 || !ok2 {
+		return WeightedPathResult[N, W]{Found: false}
+	}
+
+	distance := make(map[N]W)
+	for k, node := range a.nodes {
+		if a.reach[i][k] {
+			distance[node] = a.dist[i][k]
+		}
+	}
+
+	if !a.reach[i][j] {
+		return WeightedPathResult[N, W]{
+			Found:    false,
+			Visited:  len(distance),
+			Distance: distance,
+		}
+	}
+
+	path := []N{from}
+	for k := i; k != j; {
+		k = a.next[k][j]
+		path = append(path, a.nodes[k])
+	}
+
+	return WeightedPathResult[N, W]{
+		Found:    true,
+		Source:   from,
+		Path:     path,
+		Cost:     a.dist[i][j],
+		Visited:  len(distance),
+		Distance: distance,
+	}
+}